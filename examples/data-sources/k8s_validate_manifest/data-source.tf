@@ -0,0 +1,33 @@
+data "k8s_validate_manifest" "example" {
+  manifest = {
+    apiVersion = "apps/v1"
+    kind       = "Deployment"
+    metadata = {
+      name      = "example"
+      namespace = "default"
+    }
+    spec = {
+      replicas = 1
+      selector = {
+        matchLabels = {
+          app = "example"
+        }
+      }
+      template = {
+        metadata = {
+          labels = {
+            app = "example"
+          }
+        }
+        spec = {
+          containers = [
+            {
+              name  = "example"
+              image = "nginx:latest"
+            }
+          ]
+        }
+      }
+    }
+  }
+}
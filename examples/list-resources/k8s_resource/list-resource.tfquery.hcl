@@ -0,0 +1,9 @@
+list "k8s_resource" "example" {
+  provider = k8s
+
+  config {
+    api_version = "v1"
+    kind        = "ConfigMap"
+    namespace   = "default"
+  }
+}
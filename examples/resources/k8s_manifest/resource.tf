@@ -0,0 +1,20 @@
+resource "k8s_manifest" "example" {
+  content = <<-EOT
+    apiVersion: v1
+    kind: Namespace
+    metadata:
+      name: example
+    ---
+    apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: example
+      namespace: example
+    data:
+      foo: bar
+  EOT
+}
+
+resource "k8s_manifest" "example_file" {
+  file = "${path.module}/manifests.yaml"
+}
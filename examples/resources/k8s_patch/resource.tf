@@ -0,0 +1,16 @@
+resource "k8s_patch" "example" {
+  manifest = {
+    apiVersion = "apps/v1"
+    kind       = "DaemonSet"
+    metadata = {
+      name      = "kube-proxy"
+      namespace = "kube-system"
+      annotations = {
+        "example.com/owner" = "platform"
+      }
+    }
+  }
+
+  field_manager   = "example-kube-proxy-patch"
+  force_conflicts = true
+}
@@ -0,0 +1,9 @@
+import {
+  to = k8s_resource.example
+  identity = {
+    api_version = "v1"
+    kind        = "ConfigMap"
+    namespace   = "default"
+    name        = "example"
+  }
+}
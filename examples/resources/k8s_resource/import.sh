@@ -0,0 +1,5 @@
+# Namespaced resources are imported using the ID format apiVersion//kind//namespace//name.
+terraform import k8s_resource.example v1//ConfigMap//default//example
+
+# Cluster scoped resources are imported using the ID format apiVersion//kind//name.
+terraform import k8s_resource.example v1//Namespace//example
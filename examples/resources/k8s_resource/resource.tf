@@ -11,3 +11,47 @@ resource "k8s_resource" "example" {
     }
   }
 }
+
+resource "k8s_resource" "deployment" {
+  manifest = {
+    apiVersion = "apps/v1"
+    kind       = "Deployment"
+    metadata = {
+      name      = "example"
+      namespace = "default"
+    }
+    spec = {
+      replicas = 2
+      selector = {
+        matchLabels = {
+          app = "example"
+        }
+      }
+      template = {
+        metadata = {
+          labels = {
+            app = "example"
+          }
+        }
+        spec = {
+          containers = [
+            {
+              name  = "example"
+              image = "nginx:stable"
+            }
+          ]
+        }
+      }
+    }
+  }
+
+  wait = {
+    rollout = true
+    conditions = [
+      {
+        type   = "Available"
+        status = "True"
+      }
+    ]
+  }
+}
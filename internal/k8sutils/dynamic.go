@@ -1,13 +1,27 @@
 package k8sutils
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 )
 
+// DefaultMappingBackoff is the default backoff for retrying the REST mapping of a kind the REST mapper doesn't know; the
+// retries give up after about 30 seconds, which is long enough for a new custom resource definition to be established
+// without making a misspelled kind wait for the whole operation timeout.
+var DefaultMappingBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    6,
+	Cap:      10 * time.Second,
+}
+
 // GetMapping returns a REST mapping for the given REST mapper and GroupVersionKind.
 func GetMapping(m meta.RESTMapper, gvk *schema.GroupVersionKind) (*meta.RESTMapping, error) {
 	mapping, err := m.RESTMapping(gvk.GroupKind(), gvk.Version)
@@ -17,6 +31,29 @@ func GetMapping(m meta.RESTMapper, gvk *schema.GroupVersionKind) (*meta.RESTMapp
 	return mapping, nil
 }
 
+// GetMappingWithRetry returns a REST mapping for the given REST mapper and GroupVersionKind; if the kind isn't known the
+// REST mapper is reset, which invalidates its cached discovery information, and the mapping is retried with backoff
+// until it's found, the backoff steps are used up or the context is done. This allows kinds defined by custom resource
+// definitions created in the same operation to be resolved; if the kind is still unknown the no match error is returned.
+func GetMappingWithRetry(ctx context.Context, m meta.ResettableRESTMapper, gvk *schema.GroupVersionKind, backoff wait.Backoff) (*meta.RESTMapping, error) {
+	mapping, err := GetMapping(m, gvk)
+	for meta.IsNoMatchError(err) {
+		m.Reset()
+
+		if mapping, err = GetMapping(m, gvk); !meta.IsNoMatchError(err) || backoff.Steps <= 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff.Step()):
+		}
+	}
+
+	return mapping, err
+}
+
 // GetResourceInterface returns a dynamic resource interface for the given REST mapping and namespace.
 func GetResourceInterface(c dynamic.Interface, mapping *meta.RESTMapping, requireNamespace bool, namespace string) (dynamic.ResourceInterface, error) {
 	res := c.Resource(mapping.Resource)
@@ -1,12 +1,16 @@
 package k8sutils
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"regexp"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 )
 
@@ -70,6 +74,102 @@ func TestGetMapping(t *testing.T) {
 	}
 }
 
+func TestGetMappingWithRetry(t *testing.T) {
+	t.Parallel()
+
+	gvk := &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Foo"}
+	results := map[string]restMapperResult{
+		"foo.example.com": {
+			mapping: &meta.RESTMapping{},
+		},
+	}
+
+	for _, tt := range []struct {
+		testName   string
+		mapper     *restMapperStub
+		timeout    time.Duration
+		wantResets int
+		wantErr    *string
+	}{
+		{
+			testName:   "known_kind",
+			mapper:     &restMapperStub{results: results},
+			timeout:    time.Minute,
+			wantResets: 0,
+		},
+		{
+			testName:   "known_after_reset",
+			mapper:     &restMapperStub{results: results, noMatches: 1},
+			timeout:    time.Minute,
+			wantResets: 1,
+		},
+		{
+			testName:   "known_after_retries",
+			mapper:     &restMapperStub{results: results, noMatches: 3},
+			timeout:    time.Minute,
+			wantResets: 3,
+		},
+		{
+			testName:   "retries_exhausted",
+			mapper:     &restMapperStub{results: results, noMatches: math.MaxInt},
+			timeout:    time.Minute,
+			wantResets: 4,
+			wantErr:    new(`failed to get rest mapping: no matches for kind "Foo" in version "example.com/v1"`),
+		},
+		{
+			testName:   "context_done",
+			mapper:     &restMapperStub{results: results, noMatches: math.MaxInt},
+			timeout:    0,
+			wantResets: 1,
+			wantErr:    new(`failed to get rest mapping: no matches for kind "Foo" in version "example.com/v1"`),
+		},
+		{
+			testName: "failure",
+			mapper: &restMapperStub{
+				results: map[string]restMapperResult{
+					"foo.example.com": {
+						err: fmt.Errorf("server error"),
+					},
+				},
+			},
+			timeout: time.Minute,
+			wantErr: new("failed to get rest mapping: server error"),
+		},
+	} {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(t.Context(), tt.timeout)
+			defer cancel()
+
+			got, err := GetMappingWithRetry(ctx, tt.mapper, gvk, wait.Backoff{Duration: time.Millisecond, Factor: 2, Steps: 3, Cap: 10 * time.Millisecond})
+			if tt.mapper.resets != tt.wantResets {
+				t.Errorf("GetMappingWithRetry() reset the REST mapper %d times, want %d", tt.mapper.resets, tt.wantResets)
+			}
+
+			if err != nil {
+				if tt.wantErr == nil {
+					t.Errorf("GetMappingWithRetry() returned unexpected error: %v", err)
+				}
+
+				if !regexp.MustCompile(regexp.QuoteMeta(*tt.wantErr)).MatchString(err.Error()) {
+					t.Errorf("GetMappingWithRetry() returned error %q, want %q", err.Error(), *tt.wantErr)
+				}
+
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Errorf("GetMappingWithRetry() returned no error, want %q", *tt.wantErr)
+			}
+
+			if got == nil {
+				t.Errorf("GetMappingWithRetry() returned nil, want non-nil")
+			}
+		})
+	}
+}
+
 func TestGetResourceInterface(t *testing.T) {
 	t.Parallel()
 
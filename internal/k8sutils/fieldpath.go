@@ -0,0 +1,174 @@
+package k8sutils
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GetFieldValue returns the value of the object field at the given path; the path is made up of field names separated by
+// dots, list indexes in brackets (e.g. `[0]`) and quoted field names in brackets for names containing dots
+// (e.g. `["app.kubernetes.io/name"]`).
+func GetFieldValue(obj map[string]any, path string) (any, bool, error) {
+	segments, err := parseFieldPath(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if slices.ContainsFunc(segments, func(s fieldPathSegment) bool { return s.index == wildcardIndex }) {
+		return nil, false, fmt.Errorf("invalid field path %q: wildcards aren't supported", path)
+	}
+
+	var v any = obj
+	for _, s := range segments {
+		switch vv := v.(type) {
+		case map[string]any:
+			if s.index != fieldNameIndex {
+				return nil, false, nil
+			}
+
+			var ok bool
+			if v, ok = vv[s.name]; !ok {
+				return nil, false, nil
+			}
+		case []any:
+			if s.index < 0 || s.index >= len(vv) {
+				return nil, false, nil
+			}
+
+			v = vv[s.index]
+		default:
+			return nil, false, nil
+		}
+	}
+
+	return v, true, nil
+}
+
+// RemoveFieldPath removes the field at the given path from the object in place; the path uses the same syntax as
+// GetFieldValue and can also contain `[*]` to match all list items (e.g. `metadata.managedFields[*].time`). A trailing
+// `[*]` removes the list field itself, so `spec.foo[*]` is the same as `spec.foo`.
+func RemoveFieldPath(obj map[string]any, path string) error {
+	segments, err := parseFieldPath(path)
+	if err != nil {
+		return err
+	}
+
+	for len(segments) > 0 && segments[len(segments)-1].index == wildcardIndex {
+		segments = segments[:len(segments)-1]
+	}
+
+	if len(segments) == 0 {
+		return fmt.Errorf("invalid field path %q: no field to remove", path)
+	}
+
+	removeFieldPath(obj, segments)
+
+	return nil
+}
+
+// removeFieldPath removes the field described by the segments from the value and returns the updated value.
+func removeFieldPath(v any, segments []fieldPathSegment) any {
+	s := segments[0]
+	last := len(segments) == 1
+
+	switch vv := v.(type) {
+	case map[string]any:
+		if s.index != fieldNameIndex {
+			return v
+		}
+
+		if last {
+			delete(vv, s.name)
+			return vv
+		}
+
+		if c, ok := vv[s.name]; ok {
+			vv[s.name] = removeFieldPath(c, segments[1:])
+		}
+
+		return vv
+	case []any:
+		switch {
+		case s.index == wildcardIndex:
+			for i := range vv {
+				vv[i] = removeFieldPath(vv[i], segments[1:])
+			}
+		case s.index >= 0 && s.index < len(vv) && last:
+			return slices.Delete(vv, s.index, s.index+1)
+		case s.index >= 0 && s.index < len(vv):
+			vv[s.index] = removeFieldPath(vv[s.index], segments[1:])
+		}
+
+		return vv
+	default:
+		return v
+	}
+}
+
+const (
+	// fieldNameIndex is the index of a field path segment for a field name.
+	fieldNameIndex = -1
+	// wildcardIndex is the index of a field path segment matching all list items.
+	wildcardIndex = -2
+)
+
+// fieldPathSegment is a segment of a field path; the index is fieldNameIndex for a field name, wildcardIndex for all
+// list items, or the index of a list item.
+type fieldPathSegment struct {
+	name  string
+	index int
+}
+
+// parseFieldPath parses a field path into its segments.
+func parseFieldPath(path string) ([]fieldPathSegment, error) {
+	var segments []fieldPathSegment
+
+	rest := path
+	for len(rest) > 0 {
+		switch {
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid field path %q: unterminated bracket", path)
+			}
+
+			key := rest[1:end]
+			if key == "*" {
+				segments = append(segments, fieldPathSegment{index: wildcardIndex})
+			} else if unquoted, err := strconv.Unquote(key); err == nil {
+				segments = append(segments, fieldPathSegment{name: unquoted, index: fieldNameIndex})
+			} else if i, err := strconv.Atoi(key); err == nil && i >= 0 {
+				segments = append(segments, fieldPathSegment{index: i})
+			} else {
+				return nil, fmt.Errorf("invalid field path %q: invalid key %q", path, key)
+			}
+
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "."):
+			if len(segments) == 0 {
+				return nil, fmt.Errorf("invalid field path %q: unexpected dot", path)
+			}
+
+			rest = rest[1:]
+			if len(rest) == 0 || rest[0] == '.' || rest[0] == '[' {
+				return nil, fmt.Errorf("invalid field path %q: unexpected dot", path)
+			}
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+
+			segments = append(segments, fieldPathSegment{name: rest[:end], index: fieldNameIndex})
+			rest = rest[end:]
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid field path %q: empty path", path)
+	}
+
+	return segments, nil
+}
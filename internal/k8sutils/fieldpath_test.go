@@ -0,0 +1,245 @@
+package k8sutils
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetFieldValue(t *testing.T) {
+	t.Parallel()
+
+	obj := map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]any{"example.com/foo": "bar"},
+		},
+		"spec": map[string]any{
+			"ports": []any{
+				map[string]any{"port": int64(80)},
+			},
+		},
+	}
+
+	for _, d := range []struct {
+		testName string
+		path     string
+		want     any
+		wantOk   bool
+		wantErr  *string
+	}{
+		{
+			testName: "map",
+			path:     "spec",
+			want:     map[string]any{"ports": []any{map[string]any{"port": int64(80)}}},
+			wantOk:   true,
+		},
+		{
+			testName: "nested_list",
+			path:     "spec.ports[0].port",
+			want:     int64(80),
+			wantOk:   true,
+		},
+		{
+			testName: "quoted_key",
+			path:     `metadata.annotations["example.com/foo"]`,
+			want:     "bar",
+			wantOk:   true,
+		},
+		{
+			testName: "missing_field",
+			path:     "spec.selector",
+		},
+		{
+			testName: "index_out_of_range",
+			path:     "spec.ports[1]",
+		},
+		{
+			testName: "index_into_map",
+			path:     "spec[0]",
+		},
+		{
+			testName: "field_of_scalar",
+			path:     "spec.ports[0].port.foo",
+		},
+		{
+			testName: "empty",
+			path:     "",
+			wantErr:  new(`invalid field path "": empty path`),
+		},
+		{
+			testName: "leading_dot",
+			path:     ".spec",
+			wantErr:  new(`invalid field path ".spec": unexpected dot`),
+		},
+		{
+			testName: "trailing_dot",
+			path:     "spec.",
+			wantErr:  new(`invalid field path "spec.": unexpected dot`),
+		},
+		{
+			testName: "unterminated_bracket",
+			path:     "spec.ports[0",
+			wantErr:  new(`invalid field path "spec.ports[0": unterminated bracket`),
+		},
+		{
+			testName: "invalid_key",
+			path:     "spec.ports[foo]",
+			wantErr:  new(`invalid field path "spec.ports[foo]": invalid key "foo"`),
+		},
+		{
+			testName: "wildcard",
+			path:     "spec.ports[*].port",
+			wantErr:  new(`invalid field path "spec.ports[*].port": wildcards aren't supported`),
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok, err := GetFieldValue(obj, d.path)
+			if err != nil {
+				if d.wantErr == nil {
+					t.Errorf("GetFieldValue() returned unexpected error: %v", err)
+				}
+
+				if !regexp.MustCompile(regexp.QuoteMeta(*d.wantErr)).MatchString(err.Error()) {
+					t.Errorf("GetFieldValue() returned error %q, want %q", err.Error(), *d.wantErr)
+				}
+
+				return
+			}
+
+			if d.wantErr != nil {
+				t.Errorf("GetFieldValue() returned no error, want %q", *d.wantErr)
+			}
+
+			if ok != d.wantOk {
+				t.Errorf("GetFieldValue() ok = %t, want %t", ok, d.wantOk)
+			}
+
+			if diff := cmp.Diff(d.want, got); diff != "" {
+				t.Errorf("GetFieldValue() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRemoveFieldPath(t *testing.T) {
+	t.Parallel()
+
+	newObj := func() map[string]any {
+		return map[string]any{
+			"metadata": map[string]any{
+				"name":        "foo",
+				"annotations": map[string]any{"example.com/foo": "bar", "baz": "qux"},
+				"managedFields": []any{
+					map[string]any{"manager": "a", "time": "2024-01-01T00:00:00Z"},
+					map[string]any{"manager": "b", "time": "2024-01-02T00:00:00Z"},
+				},
+			},
+			"spec": map[string]any{
+				"ports": []any{int64(80), int64(443)},
+			},
+		}
+	}
+
+	for _, d := range []struct {
+		testName string
+		path     string
+		want     map[string]any
+		wantErr  *string
+	}{
+		{
+			testName: "field",
+			path:     "spec",
+			want: map[string]any{
+				"metadata": newObj()["metadata"],
+			},
+		},
+		{
+			testName: "quoted_key",
+			path:     `metadata.annotations["example.com/foo"]`,
+			want: func() map[string]any {
+				o := newObj()
+				delete(o["metadata"].(map[string]any)["annotations"].(map[string]any), "example.com/foo")
+				return o
+			}(),
+		},
+		{
+			testName: "wildcard",
+			path:     "metadata.managedFields[*].time",
+			want: func() map[string]any {
+				o := newObj()
+				o["metadata"].(map[string]any)["managedFields"] = []any{
+					map[string]any{"manager": "a"},
+					map[string]any{"manager": "b"},
+				}
+				return o
+			}(),
+		},
+		{
+			testName: "trailing_wildcard",
+			path:     "spec.ports[*]",
+			want: func() map[string]any {
+				o := newObj()
+				o["spec"] = map[string]any{}
+				return o
+			}(),
+		},
+		{
+			testName: "only_wildcard",
+			path:     "[*]",
+			wantErr:  new(`invalid field path "[*]": no field to remove`),
+		},
+		{
+			testName: "list_item",
+			path:     "spec.ports[0]",
+			want: func() map[string]any {
+				o := newObj()
+				o["spec"] = map[string]any{"ports": []any{int64(443)}}
+				return o
+			}(),
+		},
+		{
+			testName: "missing_field",
+			path:     "spec.selector.app",
+			want:     newObj(),
+		},
+		{
+			testName: "index_out_of_range",
+			path:     "spec.ports[2]",
+			want:     newObj(),
+		},
+		{
+			testName: "invalid_path",
+			path:     "spec.",
+			wantErr:  new(`invalid field path "spec.": unexpected dot`),
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got := newObj()
+			err := RemoveFieldPath(got, d.path)
+			if err != nil {
+				if d.wantErr == nil {
+					t.Errorf("RemoveFieldPath() returned unexpected error: %v", err)
+				}
+
+				if !regexp.MustCompile(regexp.QuoteMeta(*d.wantErr)).MatchString(err.Error()) {
+					t.Errorf("RemoveFieldPath() returned error %q, want %q", err.Error(), *d.wantErr)
+				}
+
+				return
+			}
+
+			if d.wantErr != nil {
+				t.Errorf("RemoveFieldPath() returned no error, want %q", *d.wantErr)
+			}
+
+			if diff := cmp.Diff(d.want, got); diff != "" {
+				t.Errorf("RemoveFieldPath() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -59,10 +59,17 @@ type restMapperResult struct {
 type restMapperStub struct {
 	meta.ResettableRESTMapper
 
-	results map[string]restMapperResult
+	results   map[string]restMapperResult
+	noMatches int
+	resets    int
 }
 
-func (m *restMapperStub) RESTMapping(gk schema.GroupKind, _ ...string) (*meta.RESTMapping, error) {
+func (m *restMapperStub) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	if m.noMatches > 0 {
+		m.noMatches--
+		return nil, &meta.NoKindMatchError{GroupKind: gk, SearchedVersions: versions}
+	}
+
 	k := strings.ToLower(gk.String())
 	res, ok := m.results[k]
 	if !ok {
@@ -70,3 +77,7 @@ func (m *restMapperStub) RESTMapping(gk schema.GroupKind, _ ...string) (*meta.RE
 	}
 	return res.mapping, res.err
 }
+
+func (m *restMapperStub) Reset() {
+	m.resets++
+}
@@ -0,0 +1,209 @@
+package k8sutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ExtractManagedFields returns the fields of the object applied by the given field manager, as recorded in
+// `metadata.managedFields`; the object identity (`apiVersion`, `kind`, `metadata.name` and `metadata.namespace`) is always
+// included. The boolean return value reports whether the field manager owns any fields of the object.
+func ExtractManagedFields(obj *unstructured.Unstructured, manager string) (map[string]any, bool, error) {
+	var fields map[string]any
+	for _, mf := range obj.GetManagedFields() {
+		if mf.Manager != manager || mf.Operation != metav1.ManagedFieldsOperationApply || len(mf.Subresource) != 0 || mf.FieldsV1 == nil {
+			continue
+		}
+
+		if err := json.Unmarshal(mf.FieldsV1.Raw, &fields); err != nil {
+			return nil, false, fmt.Errorf("failed to parse managed fields: %w", err)
+		}
+
+		break
+	}
+
+	var out map[string]any
+	if fields != nil {
+		v, err := extractFields(obj.Object, fields)
+		if err != nil {
+			return nil, false, err
+		}
+
+		out, _ = v.(map[string]any)
+	}
+
+	if out == nil {
+		out = map[string]any{}
+	}
+
+	out["apiVersion"] = obj.GetAPIVersion()
+	out["kind"] = obj.GetKind()
+
+	metadata, _ := out["metadata"].(map[string]any)
+	if metadata == nil {
+		metadata = map[string]any{}
+		out["metadata"] = metadata
+	}
+
+	metadata["name"] = obj.GetName()
+	if len(obj.GetNamespace()) > 0 {
+		metadata["namespace"] = obj.GetNamespace()
+	}
+
+	return out, fields != nil, nil
+}
+
+// extractFields returns the parts of the value described by the managed fields set.
+func extractFields(v any, fields map[string]any) (any, error) {
+	if len(fields) == 0 {
+		return v, nil
+	}
+
+	switch vv := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(fields))
+		for k, f := range fields {
+			name, ok := strings.CutPrefix(k, "f:")
+			if !ok {
+				continue
+			}
+
+			fv, ok := vv[name]
+			if !ok {
+				continue
+			}
+
+			sub, _ := f.(map[string]any)
+			ev, err := extractFields(fv, sub)
+			if err != nil {
+				return nil, err
+			}
+
+			out[name] = ev
+		}
+
+		return out, nil
+	case []any:
+		out := make([]any, 0, len(vv))
+		for i, item := range vv {
+			for k, f := range fields {
+				ok, err := matchListItem(k, i, item)
+				if err != nil {
+					return nil, err
+				}
+
+				if !ok {
+					continue
+				}
+
+				sub, _ := f.(map[string]any)
+				ev, err := extractFields(item, sub)
+				if err != nil {
+					return nil, err
+				}
+
+				if key, ok := strings.CutPrefix(k, "k:"); ok {
+					ev, err = mergeListItemKeys(ev, item, key)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				out = append(out, ev)
+				break
+			}
+		}
+
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// matchListItem reports whether the list item at the given index is described by the managed fields key.
+func matchListItem(key string, index int, item any) (bool, error) {
+	switch {
+	case strings.HasPrefix(key, "k:"):
+		var keys map[string]any
+		if err := json.Unmarshal([]byte(key[2:]), &keys); err != nil {
+			return false, fmt.Errorf("failed to parse managed fields key %q: %w", key, err)
+		}
+
+		m, ok := item.(map[string]any)
+		if !ok {
+			return false, nil
+		}
+
+		for k, v := range keys {
+			if !jsonEqual(m[k], v) {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	case strings.HasPrefix(key, "v:"):
+		var value any
+		if err := json.Unmarshal([]byte(key[2:]), &value); err != nil {
+			return false, fmt.Errorf("failed to parse managed fields key %q: %w", key, err)
+		}
+
+		return jsonEqual(item, value), nil
+	case strings.HasPrefix(key, "i:"):
+		i, err := strconv.Atoi(key[2:])
+		if err != nil {
+			return false, fmt.Errorf("failed to parse managed fields key %q: %w", key, err)
+		}
+
+		return i == index, nil
+	default:
+		return false, nil
+	}
+}
+
+// mergeListItemKeys adds the key fields of the list item to the extracted item, as these identify the item even if
+// they aren't owned by the field manager.
+func mergeListItemKeys(extracted, item any, key string) (any, error) {
+	em, ok := extracted.(map[string]any)
+	if !ok {
+		return extracted, nil
+	}
+
+	im, ok := item.(map[string]any)
+	if !ok {
+		return extracted, nil
+	}
+
+	var keys map[string]any
+	if err := json.Unmarshal([]byte(key), &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse managed fields key %q: %w", key, err)
+	}
+
+	for k := range keys {
+		if _, ok := em[k]; !ok {
+			em[k] = im[k]
+		}
+	}
+
+	return em, nil
+}
+
+// jsonEqual reports whether the two values have the same JSON representation.
+func jsonEqual(a, b any) bool {
+	ab, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+
+	bb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(ab, bb)
+}
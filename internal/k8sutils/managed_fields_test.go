@@ -0,0 +1,215 @@
+package k8sutils
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestExtractManagedFields(t *testing.T) {
+	t.Parallel()
+
+	deployment := func(managedFields ...any) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name":          "foo",
+				"namespace":     "default",
+				"labels":        map[string]any{"app": "foo", "team": "bar"},
+				"managedFields": managedFields,
+			},
+			"spec": map[string]any{
+				"replicas": int64(3),
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": []any{
+							map[string]any{
+								"name":  "app",
+								"image": "nginx",
+								"ports": []any{
+									map[string]any{"containerPort": int64(80), "protocol": "TCP"},
+								},
+								"args": []any{"--foo", "--bar"},
+							},
+							map[string]any{
+								"name":  "sidecar",
+								"image": "busybox",
+							},
+						},
+						"finalizers": []any{"foo", "bar"},
+					},
+				},
+			},
+		}}
+	}
+
+	for _, d := range []struct {
+		testName  string
+		in        *unstructured.Unstructured
+		manager   string
+		want      map[string]any
+		wantOwned bool
+		wantErr   *string
+	}{
+		{
+			testName: "no_managed_fields",
+			in:       deployment(),
+			manager:  "test",
+			want: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "foo", "namespace": "default"},
+			},
+			wantOwned: false,
+		},
+		{
+			testName: "other_manager",
+			in: deployment(map[string]any{
+				"manager":    "kubectl",
+				"operation":  "Apply",
+				"fieldsType": "FieldsV1",
+				"fieldsV1":   map[string]any{"f:spec": map[string]any{"f:replicas": map[string]any{}}},
+			}),
+			manager: "test",
+			want: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "foo", "namespace": "default"},
+			},
+			wantOwned: false,
+		},
+		{
+			testName: "update_operation",
+			in: deployment(map[string]any{
+				"manager":    "test",
+				"operation":  "Update",
+				"fieldsType": "FieldsV1",
+				"fieldsV1":   map[string]any{"f:spec": map[string]any{"f:replicas": map[string]any{}}},
+			}),
+			manager: "test",
+			want: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "foo", "namespace": "default"},
+			},
+			wantOwned: false,
+		},
+		{
+			testName: "owned_fields",
+			in: deployment(map[string]any{
+				"manager":    "test",
+				"operation":  "Apply",
+				"fieldsType": "FieldsV1",
+				"fieldsV1": map[string]any{
+					"f:metadata": map[string]any{
+						"f:labels": map[string]any{"f:app": map[string]any{}},
+					},
+					"f:spec": map[string]any{
+						"f:template": map[string]any{
+							"f:spec": map[string]any{
+								"f:containers": map[string]any{
+									`k:{"name":"app"}`: map[string]any{
+										".":       map[string]any{},
+										"f:image": map[string]any{},
+										"f:ports": map[string]any{
+											`k:{"containerPort":80,"protocol":"TCP"}`: map[string]any{
+												".":               map[string]any{},
+												"f:containerPort": map[string]any{},
+											},
+										},
+										"f:args": map[string]any{},
+									},
+								},
+								"f:finalizers": map[string]any{
+									`v:"bar"`: map[string]any{},
+								},
+							},
+						},
+					},
+				},
+			}),
+			manager: "test",
+			want: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]any{
+					"name":      "foo",
+					"namespace": "default",
+					"labels":    map[string]any{"app": "foo"},
+				},
+				"spec": map[string]any{
+					"template": map[string]any{
+						"spec": map[string]any{
+							"containers": []any{
+								map[string]any{
+									"name":  "app",
+									"image": "nginx",
+									"ports": []any{
+										map[string]any{"containerPort": int64(80), "protocol": "TCP"},
+									},
+									"args": []any{"--foo", "--bar"},
+								},
+							},
+							"finalizers": []any{"bar"},
+						},
+					},
+				},
+			},
+			wantOwned: true,
+		},
+		{
+			testName: "invalid_key",
+			in: deployment(map[string]any{
+				"manager":    "test",
+				"operation":  "Apply",
+				"fieldsType": "FieldsV1",
+				"fieldsV1": map[string]any{
+					"f:spec": map[string]any{
+						"f:template": map[string]any{
+							"f:spec": map[string]any{
+								"f:containers": map[string]any{
+									`k:{"name":`: map[string]any{},
+								},
+							},
+						},
+					},
+				},
+			}),
+			manager: "test",
+			wantErr: new("failed to parse managed fields key"),
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got, owned, err := ExtractManagedFields(d.in, d.manager)
+			if err != nil {
+				if d.wantErr == nil {
+					t.Errorf("ExtractManagedFields() returned unexpected error: %v", err)
+				}
+
+				if !regexp.MustCompile(regexp.QuoteMeta(*d.wantErr)).MatchString(err.Error()) {
+					t.Errorf("ExtractManagedFields() returned error %q, want %q", err.Error(), *d.wantErr)
+				}
+
+				return
+			}
+
+			if d.wantErr != nil {
+				t.Errorf("ExtractManagedFields() returned no error, want %q", *d.wantErr)
+			}
+
+			if owned != d.wantOwned {
+				t.Errorf("ExtractManagedFields() returned owned %t, want %t", owned, d.wantOwned)
+			}
+
+			if diff := cmp.Diff(d.want, got); diff != "" {
+				t.Errorf("ExtractManagedFields() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package k8sutils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// DecodeManifests decodes a stream of YAML or JSON documents into objects; empty documents are skipped and `List`
+// objects are expanded into their items. Every object must contain `apiVersion`, `kind` and `metadata.name`.
+func DecodeManifests(r io.Reader) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+
+	d := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for i := 0; ; i++ {
+		var m map[string]any
+		if err := d.Decode(&m); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode document %d: %w", i, err)
+		}
+
+		if len(m) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{Object: m}
+		if !obj.IsList() {
+			if err := validateManifest(obj); err != nil {
+				return nil, fmt.Errorf("invalid document %d: %w", i, err)
+			}
+
+			objs = append(objs, obj)
+			continue
+		}
+
+		err := obj.EachListItem(func(o runtime.Object) error {
+			item, ok := o.(*unstructured.Unstructured)
+			if !ok {
+				return fmt.Errorf("unexpected list item type: %T", o)
+			}
+
+			if err := validateManifest(item); err != nil {
+				return err
+			}
+
+			objs = append(objs, item)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid document %d: %w", i, err)
+		}
+	}
+
+	return objs, nil
+}
+
+// SortByApplyOrder sorts the objects into the order they need to be applied in; namespaces come first, followed by
+// custom resource definitions and then all other objects in their original order.
+func SortByApplyOrder(objs []*unstructured.Unstructured) {
+	slices.SortStableFunc(objs, func(a, b *unstructured.Unstructured) int {
+		return applyOrder(a) - applyOrder(b)
+	})
+}
+
+// applyOrder returns the apply order rank of the object.
+func applyOrder(obj *unstructured.Unstructured) int {
+	gvk := obj.GroupVersionKind()
+	switch {
+	case len(gvk.Group) == 0 && gvk.Kind == "Namespace":
+		return 0
+	case gvk.Group == "apiextensions.k8s.io" && gvk.Kind == "CustomResourceDefinition":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// validateManifest checks that the object contains the fields identifying it.
+func validateManifest(obj *unstructured.Unstructured) error {
+	if len(obj.GetAPIVersion()) == 0 || len(obj.GetKind()) == 0 || len(obj.GetName()) == 0 {
+		return fmt.Errorf("the manifest must contain apiVersion, kind and metadata.name")
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package k8sutils
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDecodeManifests(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []struct {
+		testName string
+		in       string
+		want     []map[string]any
+		wantErr  *string
+	}{
+		{
+			testName: "empty",
+			in:       "",
+			want:     []map[string]any{},
+		},
+		{
+			testName: "yaml_documents",
+			in: `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: foo
+---
+# comment only
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: bar
+  namespace: foo
+data:
+  baz: qux
+`,
+			want: []map[string]any{
+				{"apiVersion": "v1", "kind": "Namespace", "metadata": map[string]any{"name": "foo"}},
+				{"apiVersion": "v1", "kind": "ConfigMap", "metadata": map[string]any{"name": "bar", "namespace": "foo"}, "data": map[string]any{"baz": "qux"}},
+			},
+		},
+		{
+			testName: "json_document",
+			in:       `{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "foo"}}`,
+			want: []map[string]any{
+				{"apiVersion": "v1", "kind": "Namespace", "metadata": map[string]any{"name": "foo"}},
+			},
+		},
+		{
+			testName: "list",
+			in: `apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: foo
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: bar
+`,
+			want: []map[string]any{
+				{"apiVersion": "v1", "kind": "Namespace", "metadata": map[string]any{"name": "foo"}},
+				{"apiVersion": "v1", "kind": "Namespace", "metadata": map[string]any{"name": "bar"}},
+			},
+		},
+		{
+			testName: "missing_name",
+			in: `apiVersion: v1
+kind: Namespace
+`,
+			wantErr: new("invalid document 0: the manifest must contain apiVersion, kind and metadata.name"),
+		},
+		{
+			testName: "invalid_yaml",
+			in:       "apiVersion: [",
+			wantErr:  new("failed to decode document 0"),
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := DecodeManifests(strings.NewReader(d.in))
+			if err != nil {
+				if d.wantErr == nil {
+					t.Errorf("DecodeManifests() returned unexpected error: %v", err)
+				}
+
+				if !regexp.MustCompile(regexp.QuoteMeta(*d.wantErr)).MatchString(err.Error()) {
+					t.Errorf("DecodeManifests() returned error %q, want %q", err.Error(), *d.wantErr)
+				}
+
+				return
+			}
+
+			if d.wantErr != nil {
+				t.Errorf("DecodeManifests() returned no error, want %q", *d.wantErr)
+			}
+
+			objs := make([]map[string]any, 0, len(got))
+			for _, o := range got {
+				objs = append(objs, o.Object)
+			}
+
+			if diff := cmp.Diff(d.want, objs); diff != "" {
+				t.Errorf("DecodeManifests() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSortByApplyOrder(t *testing.T) {
+	t.Parallel()
+
+	newObj := func(apiVersion, kind, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{"apiVersion": apiVersion, "kind": kind, "metadata": map[string]any{"name": name}}}
+	}
+
+	objs := []*unstructured.Unstructured{
+		newObj("v1", "ConfigMap", "a"),
+		newObj("example.com/v1", "Foo", "b"),
+		newObj("apiextensions.k8s.io/v1", "CustomResourceDefinition", "c"),
+		newObj("v1", "Namespace", "d"),
+		newObj("v1", "ConfigMap", "e"),
+		newObj("v1", "Namespace", "f"),
+	}
+
+	SortByApplyOrder(objs)
+
+	got := make([]string, 0, len(objs))
+	for _, o := range objs {
+		got = append(got, o.GetName())
+	}
+
+	if diff := cmp.Diff([]string{"d", "f", "c", "a", "b", "e"}, got); diff != "" {
+		t.Errorf("SortByApplyOrder() mismatch (-want +got):\n%s", diff)
+	}
+}
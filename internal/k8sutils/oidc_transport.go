@@ -0,0 +1,328 @@
+package k8sutils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultOIDCExpiryDelta is how long before the ID token expires it's refreshed.
+const DefaultOIDCExpiryDelta = time.Minute
+
+// OIDCOptions configures OIDC authentication.
+type OIDCOptions struct {
+	// IssuerURL is the URL of the OIDC issuer; the token endpoint is discovered from its OpenID configuration.
+	IssuerURL string
+	// ClientID is the ID of the OIDC client.
+	ClientID string
+	// ClientSecret is the secret of the OIDC client, this is empty for public clients.
+	ClientSecret string
+	// RefreshToken is the refresh token used to get a new ID token.
+	RefreshToken string
+	// IDToken is the initial ID token, if this is empty a token is requested with the refresh token.
+	IDToken string
+	// CAData is the PEM encoded CA certificate used to verify the issuer; if empty the system CAs are used.
+	CAData []byte
+	// ExpiryDelta is how long before the ID token expires it's refreshed.
+	ExpiryDelta time.Duration
+	// Proxy returns the proxy for the issuer requests; if nil the proxy is read from the environment.
+	Proxy func(*http.Request) (*url.URL, error)
+}
+
+// OIDCTokenSource returns OIDC ID tokens, refreshing them with the refresh token when they're about to expire.
+type OIDCTokenSource struct {
+	opts          OIDCOptions
+	client        *http.Client
+	mutex         sync.Mutex
+	tokenEndpoint string
+	idToken       string
+	refreshToken  string
+	expiry        time.Time
+}
+
+// NewOIDCTokenSource creates a new OIDC token source.
+func NewOIDCTokenSource(opts OIDCOptions) (*OIDCTokenSource, error) {
+	if len(opts.IssuerURL) == 0 {
+		return nil, errors.New("issuer URL is required")
+	}
+
+	if len(opts.ClientID) == 0 {
+		return nil, errors.New("client ID is required")
+	}
+
+	if len(opts.IDToken) == 0 && len(opts.RefreshToken) == 0 {
+		return nil, errors.New("either an ID token or a refresh token is required")
+	}
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("expected *http.Transport, got %T", http.DefaultTransport)
+	}
+	transport = transport.Clone()
+
+	if opts.Proxy != nil {
+		transport.Proxy = opts.Proxy
+	}
+
+	if len(opts.CAData) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(opts.CAData) {
+			return nil, errors.New("failed to parse CA certificate")
+		}
+
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	s := &OIDCTokenSource{
+		opts:         opts,
+		client:       &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		refreshToken: opts.RefreshToken,
+	}
+
+	if len(opts.IDToken) > 0 {
+		expiry, err := tokenExpiry(opts.IDToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ID token: %w", err)
+		}
+
+		s.idToken = opts.IDToken
+		s.expiry = expiry
+	}
+
+	return s, nil
+}
+
+// Token returns a valid ID token, refreshing it if it's about to expire.
+func (s *OIDCTokenSource) Token(ctx context.Context) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.idToken) > 0 && time.Now().Add(s.opts.ExpiryDelta).Before(s.expiry) {
+		return s.idToken, nil
+	}
+
+	if len(s.refreshToken) == 0 {
+		return "", errors.New("ID token has expired and there is no refresh token")
+	}
+
+	if err := s.refresh(ctx); err != nil {
+		return "", err
+	}
+
+	return s.idToken, nil
+}
+
+// invalidate discards the ID token if it's still the current one, so the next call to Token refreshes it; this returns
+// false if the token can't be refreshed as there is no refresh token.
+func (s *OIDCTokenSource) invalidate(token string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.refreshToken) == 0 {
+		return false
+	}
+
+	if s.idToken == token {
+		s.idToken = ""
+		s.expiry = time.Time{}
+	}
+
+	return true
+}
+
+// refresh exchanges the refresh token for a new ID token; if a new refresh token is returned it replaces the old one.
+func (s *OIDCTokenSource) refresh(ctx context.Context) error {
+	if len(s.tokenEndpoint) == 0 {
+		tokenEndpoint, err := s.discoverTokenEndpoint(ctx)
+		if err != nil {
+			return err
+		}
+
+		s.tokenEndpoint = tokenEndpoint
+	}
+
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {s.refreshToken},
+		"scope":         {"openid"},
+	}
+	if len(s.opts.ClientSecret) == 0 {
+		form.Set("client_id", s.opts.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if len(s.opts.ClientSecret) > 0 {
+		req.SetBasicAuth(url.QueryEscape(s.opts.ClientID), url.QueryEscape(s.opts.ClientSecret))
+	}
+
+	var tokenResp struct {
+		IDToken      string `json:"id_token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := s.do(req, &tokenResp); err != nil {
+		return fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	if len(tokenResp.IDToken) == 0 {
+		return errors.New("token response doesn't contain an ID token")
+	}
+
+	expiry, err := tokenExpiry(tokenResp.IDToken)
+	if err != nil {
+		return fmt.Errorf("failed to parse ID token: %w", err)
+	}
+
+	s.idToken = tokenResp.IDToken
+	s.expiry = expiry
+	if len(tokenResp.RefreshToken) > 0 {
+		s.refreshToken = tokenResp.RefreshToken
+	}
+
+	return nil
+}
+
+// discoverTokenEndpoint returns the token endpoint from the OpenID configuration of the issuer.
+func (s *OIDCTokenSource) discoverTokenEndpoint(ctx context.Context) (string, error) {
+	wellKnown := strings.TrimSuffix(s.opts.IssuerURL, "/") + "/.well-known/openid-configuration"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, http.NoBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to create OpenID configuration request: %w", err)
+	}
+
+	var config struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if err := s.do(req, &config); err != nil {
+		return "", fmt.Errorf("failed to get OpenID configuration: %w", err)
+	}
+
+	if len(config.TokenEndpoint) == 0 {
+		return "", errors.New("OpenID configuration doesn't contain a token endpoint")
+	}
+
+	return config.TokenEndpoint, nil
+}
+
+// do executes the request and decodes the JSON response into v.
+func (s *OIDCTokenSource) do(req *http.Request, v any) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+// tokenExpiry returns the expiry of the JWT from its exp claim; the signature isn't verified as that's done by the API
+// server.
+func tokenExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("token isn't a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode token payload: %w", err)
+	}
+
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("failed to unmarshal token claims: %w", err)
+	}
+
+	if claims.Exp == nil {
+		return time.Time{}, errors.New("token doesn't have an exp claim")
+	}
+
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse exp claim: %w", err)
+	}
+
+	return time.Unix(int64(exp), 0), nil
+}
+
+// NewOIDCRoundTripper returns a round tripper which authenticates requests with the ID token from the token source; if
+// a request is rejected with a 401 status the token is refreshed and the request retried once, as the token can be
+// revoked before it expires. Any Authorization header of the request is replaced.
+func NewOIDCRoundTripper(rt http.RoundTripper, source *OIDCTokenSource) http.RoundTripper {
+	return &oidcRoundTripper{
+		delegate: rt,
+		source:   source,
+	}
+}
+
+// oidcRoundTripper is a round tripper authenticating requests with an OIDC ID token.
+type oidcRoundTripper struct {
+	delegate http.RoundTripper
+	source   *OIDCTokenSource
+}
+
+// RoundTrip executes the request with the ID token as the bearer token.
+func (t *oidcRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OIDC token: %w", err)
+	}
+
+	resp, err := t.delegate.RoundTrip(withBearerToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || (req.Body != nil && req.GetBody == nil) || !t.source.invalidate(token) {
+		return resp, err
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	token, err = t.source.Token(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OIDC token: %w", err)
+	}
+
+	retry := withBearerToken(req, token)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+
+		retry.Body = body
+	}
+
+	return t.delegate.RoundTrip(retry)
+}
+
+// withBearerToken returns a copy of the request with the token as the bearer token.
+func withBearerToken(req *http.Request, token string) *http.Request {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return req
+}
@@ -0,0 +1,451 @@
+package k8sutils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOIDCRoundTripper(t *testing.T) {
+	t.Parallel()
+
+	validToken := testJWT(t, time.Now().Add(time.Hour))
+	expiredToken := testJWT(t, time.Now().Add(-time.Hour))
+	expiringToken := testJWT(t, time.Now().Add(30*time.Second))
+	refreshedToken := testJWT(t, time.Now().Add(2*time.Hour))
+
+	for _, d := range []struct {
+		testName         string
+		clientSecret     string
+		idToken          string
+		refreshToken     string
+		wantToken        string
+		wantRefreshes    int32
+		wantErr          bool
+		wantRefreshToken string
+	}{
+		{
+			testName:         "valid_id_token",
+			idToken:          validToken,
+			refreshToken:     "refresh-1",
+			wantToken:        validToken,
+			wantRefreshes:    0,
+			wantRefreshToken: "refresh-1",
+		},
+		{
+			testName:         "expired_id_token",
+			idToken:          expiredToken,
+			refreshToken:     "refresh-1",
+			wantToken:        refreshedToken,
+			wantRefreshes:    1,
+			wantRefreshToken: "refresh-2",
+		},
+		{
+			testName:         "expiring_id_token",
+			idToken:          expiringToken,
+			refreshToken:     "refresh-1",
+			wantToken:        refreshedToken,
+			wantRefreshes:    1,
+			wantRefreshToken: "refresh-2",
+		},
+		{
+			testName:         "refresh_token_only",
+			refreshToken:     "refresh-1",
+			wantToken:        refreshedToken,
+			wantRefreshes:    1,
+			wantRefreshToken: "refresh-2",
+		},
+		{
+			testName:         "client_secret",
+			clientSecret:     "secret",
+			refreshToken:     "refresh-1",
+			wantToken:        refreshedToken,
+			wantRefreshes:    1,
+			wantRefreshToken: "refresh-2",
+		},
+		{
+			testName: "expired_id_token_without_refresh_token",
+			idToken:  expiredToken,
+			wantErr:  true,
+		},
+		{
+			testName:      "invalid_refresh_token",
+			refreshToken:  "foo",
+			wantRefreshes: 1,
+			wantErr:       true,
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			var refreshes atomic.Int32
+			issuer := httptest.NewTLSServer(http.NewServeMux())
+			defer issuer.Close()
+
+			mux, ok := issuer.Config.Handler.(*http.ServeMux)
+			if !ok {
+				t.Fatalf("expected *http.ServeMux, got %T", issuer.Config.Handler)
+			}
+
+			mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = fmt.Fprintf(w, `{"issuer":%q,"token_endpoint":%q}`, issuer.URL, issuer.URL+"/token")
+			})
+
+			mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+				refreshes.Add(1)
+
+				if err := r.ParseForm(); err != nil {
+					t.Errorf("failed to parse token request form: %v", err)
+				}
+
+				if got := r.PostForm.Get("grant_type"); got != "refresh_token" {
+					t.Errorf("token request has grant type %q, want %q", got, "refresh_token")
+				}
+
+				if len(d.clientSecret) > 0 {
+					if id, secret, ok := r.BasicAuth(); !ok || id != "client" || secret != d.clientSecret {
+						t.Errorf("token request has basic auth %q:%q, want %q:%q", id, secret, "client", d.clientSecret)
+					}
+				} else if got := r.PostForm.Get("client_id"); got != "client" {
+					t.Errorf("token request has client ID %q, want %q", got, "client")
+				}
+
+				if r.PostForm.Get("refresh_token") != "refresh-1" {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+					return
+				}
+
+				_, _ = fmt.Fprintf(w, `{"id_token":%q,"refresh_token":"refresh-2","token_type":"Bearer"}`, refreshedToken)
+			})
+
+			var gotToken atomic.Value
+			api := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				gotToken.Store(r.Header.Get("Authorization"))
+			}))
+			defer api.Close()
+
+			source, err := NewOIDCTokenSource(OIDCOptions{
+				IssuerURL:    issuer.URL,
+				ClientID:     "client",
+				ClientSecret: d.clientSecret,
+				RefreshToken: d.refreshToken,
+				IDToken:      d.idToken,
+				CAData:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: issuer.Certificate().Raw}),
+				ExpiryDelta:  DefaultOIDCExpiryDelta,
+			})
+			if err != nil {
+				t.Fatalf("NewOIDCTokenSource() returned unexpected error: %v", err)
+			}
+
+			client := &http.Client{Transport: NewOIDCRoundTripper(http.DefaultTransport, source)}
+
+			for range 2 {
+				req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, api.URL, http.NoBody)
+				if err != nil {
+					t.Fatalf("failed to create request: %v", err)
+				}
+
+				resp, err := client.Do(req)
+				if d.wantErr {
+					if err == nil {
+						_ = resp.Body.Close()
+						t.Fatal("RoundTrip() didn't return an error")
+					}
+					break
+				}
+
+				if err != nil {
+					t.Fatalf("RoundTrip() returned unexpected error: %v", err)
+				}
+				_ = resp.Body.Close()
+
+				if got, want := gotToken.Load(), "Bearer "+d.wantToken; got != want {
+					t.Errorf("RoundTrip() sent Authorization header %q, want %q", got, want)
+				}
+			}
+
+			if got := refreshes.Load(); got != d.wantRefreshes {
+				t.Errorf("RoundTrip() refreshed the token %d times, want %d", got, d.wantRefreshes)
+			}
+
+			if !d.wantErr && source.refreshToken != d.wantRefreshToken {
+				t.Errorf("RoundTrip() left refresh token %q, want %q", source.refreshToken, d.wantRefreshToken)
+			}
+		})
+	}
+}
+
+func TestOIDCRoundTripperUnauthorized(t *testing.T) {
+	t.Parallel()
+
+	revokedToken := testJWT(t, time.Now().Add(time.Hour))
+	refreshedToken := testJWT(t, time.Now().Add(2*time.Hour))
+
+	for _, d := range []struct {
+		testName      string
+		refreshToken  string
+		acceptedToken string
+		wantStatus    int
+		wantAttempts  int32
+		wantRefreshes int32
+	}{
+		{
+			testName:      "refreshed",
+			refreshToken:  "refresh-1",
+			acceptedToken: refreshedToken,
+			wantStatus:    http.StatusOK,
+			wantAttempts:  2,
+			wantRefreshes: 1,
+		},
+		{
+			testName:     "no_refresh_token",
+			wantStatus:   http.StatusUnauthorized,
+			wantAttempts: 1,
+		},
+		{
+			testName:      "still_unauthorized",
+			refreshToken:  "refresh-1",
+			wantStatus:    http.StatusUnauthorized,
+			wantAttempts:  2,
+			wantRefreshes: 1,
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			var refreshes atomic.Int32
+			issuer := httptest.NewTLSServer(http.NewServeMux())
+			defer issuer.Close()
+
+			mux, ok := issuer.Config.Handler.(*http.ServeMux)
+			if !ok {
+				t.Fatalf("expected *http.ServeMux, got %T", issuer.Config.Handler)
+			}
+
+			mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = fmt.Fprintf(w, `{"issuer":%q,"token_endpoint":%q}`, issuer.URL, issuer.URL+"/token")
+			})
+
+			mux.HandleFunc("/token", func(w http.ResponseWriter, _ *http.Request) {
+				refreshes.Add(1)
+				_, _ = fmt.Fprintf(w, `{"id_token":%q,"token_type":"Bearer"}`, refreshedToken)
+			})
+
+			var attempts atomic.Int32
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := attempts.Add(1)
+
+				if b, _ := io.ReadAll(r.Body); string(b) != "foo" {
+					t.Errorf("request %d has body %q, want %q", n, string(b), "foo")
+				}
+
+				if r.Header.Get("Authorization") != "Bearer "+d.acceptedToken {
+					w.WriteHeader(http.StatusUnauthorized)
+				}
+			}))
+			defer api.Close()
+
+			source, err := NewOIDCTokenSource(OIDCOptions{
+				IssuerURL:    issuer.URL,
+				ClientID:     "client",
+				RefreshToken: d.refreshToken,
+				IDToken:      revokedToken,
+				CAData:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: issuer.Certificate().Raw}),
+				ExpiryDelta:  DefaultOIDCExpiryDelta,
+			})
+			if err != nil {
+				t.Fatalf("NewOIDCTokenSource() returned unexpected error: %v", err)
+			}
+
+			client := &http.Client{Transport: NewOIDCRoundTripper(http.DefaultTransport, source)}
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodPut, api.URL, bytes.NewReader([]byte("foo")))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() returned unexpected error: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != d.wantStatus {
+				t.Errorf("RoundTrip() returned status %d, want %d", resp.StatusCode, d.wantStatus)
+			}
+
+			if got := attempts.Load(); got != d.wantAttempts {
+				t.Errorf("RoundTrip() made %d attempts, want %d", got, d.wantAttempts)
+			}
+
+			if got := refreshes.Load(); got != d.wantRefreshes {
+				t.Errorf("RoundTrip() refreshed the token %d times, want %d", got, d.wantRefreshes)
+			}
+		})
+	}
+}
+
+func TestOIDCRoundTripperAuthorizationHeader(t *testing.T) {
+	t.Parallel()
+
+	var gotToken atomic.Value
+	api := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		gotToken.Store(r.Header.Get("Authorization"))
+	}))
+	defer api.Close()
+
+	idToken := testJWT(t, time.Now().Add(time.Hour))
+	source, err := NewOIDCTokenSource(OIDCOptions{
+		IssuerURL: "https://issuer.example.com",
+		ClientID:  "client",
+		IDToken:   idToken,
+	})
+	if err != nil {
+		t.Fatalf("NewOIDCTokenSource() returned unexpected error: %v", err)
+	}
+
+	client := &http.Client{Transport: NewOIDCRoundTripper(http.DefaultTransport, source)}
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, api.URL, http.NoBody)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer foo")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() returned unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if got, want := gotToken.Load(), "Bearer "+idToken; got != want {
+		t.Errorf("RoundTrip() sent Authorization header %q, want %q", got, want)
+	}
+}
+
+func TestOIDCTokenSourceProxy(t *testing.T) {
+	t.Parallel()
+
+	idToken := testJWT(t, time.Now().Add(time.Hour))
+
+	var gotHosts []string
+	var mutex sync.Mutex
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		gotHosts = append(gotHosts, r.Host)
+		mutex.Unlock()
+
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_, _ = fmt.Fprint(w, `{"token_endpoint":"http://issuer.example.com/token"}`)
+		case "/token":
+			_, _ = fmt.Fprintf(w, `{"id_token":%q}`, idToken)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatalf("failed to parse proxy URL: %v", err)
+	}
+
+	source, err := NewOIDCTokenSource(OIDCOptions{
+		IssuerURL:    "http://issuer.example.com",
+		ClientID:     "client",
+		RefreshToken: "foo",
+		Proxy:        http.ProxyURL(proxyURL),
+	})
+	if err != nil {
+		t.Fatalf("NewOIDCTokenSource() returned unexpected error: %v", err)
+	}
+
+	got, err := source.Token(t.Context())
+	if err != nil {
+		t.Fatalf("Token() returned unexpected error: %v", err)
+	}
+
+	if got != idToken {
+		t.Errorf("Token() returned %q, want %q", got, idToken)
+	}
+
+	if diff := cmp.Diff([]string{"issuer.example.com", "issuer.example.com"}, gotHosts); diff != "" {
+		t.Errorf("Token() proxied hosts mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewOIDCTokenSource(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []struct {
+		testName string
+		opts     OIDCOptions
+		wantErr  bool
+	}{
+		{
+			testName: "valid",
+			opts:     OIDCOptions{IssuerURL: "https://issuer.example.com", ClientID: "client", RefreshToken: "foo"},
+		},
+		{
+			testName: "missing_issuer_url",
+			opts:     OIDCOptions{ClientID: "client", RefreshToken: "foo"},
+			wantErr:  true,
+		},
+		{
+			testName: "missing_client_id",
+			opts:     OIDCOptions{IssuerURL: "https://issuer.example.com", RefreshToken: "foo"},
+			wantErr:  true,
+		},
+		{
+			testName: "missing_tokens",
+			opts:     OIDCOptions{IssuerURL: "https://issuer.example.com", ClientID: "client"},
+			wantErr:  true,
+		},
+		{
+			testName: "invalid_id_token",
+			opts:     OIDCOptions{IssuerURL: "https://issuer.example.com", ClientID: "client", IDToken: "foo"},
+			wantErr:  true,
+		},
+		{
+			testName: "invalid_ca",
+			opts:     OIDCOptions{IssuerURL: "https://issuer.example.com", ClientID: "client", RefreshToken: "foo", CAData: []byte("foo")},
+			wantErr:  true,
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewOIDCTokenSource(d.opts)
+			if gotErr := err != nil; gotErr != d.wantErr {
+				t.Errorf("NewOIDCTokenSource() returned error %v, want error %t", err, d.wantErr)
+			}
+		})
+	}
+}
+
+// testJWT returns an unsigned JWT expiring at exp.
+func testJWT(t *testing.T, exp time.Time) string {
+	t.Helper()
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	payload, err := json.Marshal(map[string]any{"sub": "foo", "exp": exp.Unix()})
+	if err != nil {
+		t.Fatalf("failed to marshal JWT payload: %v", err)
+	}
+
+	return fmt.Sprintf("%s.%s.sig", header, base64.RawURLEncoding.EncodeToString(payload))
+}
@@ -0,0 +1,91 @@
+package k8sutils
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const (
+	// componentSchemaRefPrefix is the prefix of references to component schemas.
+	componentSchemaRefPrefix = "#/components/schemas/"
+	// maxRefDepth is the maximum number of references followed when resolving a schema.
+	maxRefDepth = 32
+)
+
+// OpenAPISchema is the OpenAPI v3 schema of a kind, together with the component schemas it can reference.
+type OpenAPISchema struct {
+	// Schema is the schema of the kind.
+	Schema *spec.Schema
+	// Components are the component schemas of the group version document, keyed by name.
+	Components map[string]*spec.Schema
+}
+
+// NewOpenAPISchema returns the schema of the given GroupVersionKind from an OpenAPI v3 document; the schema is found
+// using the `x-kubernetes-group-version-kind` extension.
+func NewOpenAPISchema(doc *spec3.OpenAPI, gvk schema.GroupVersionKind) (*OpenAPISchema, error) {
+	if doc == nil || doc.Components == nil {
+		return nil, fmt.Errorf("openapi document has no component schemas")
+	}
+
+	for _, s := range doc.Components.Schemas {
+		if s != nil && hasGroupVersionKind(s, gvk) {
+			return &OpenAPISchema{Schema: s, Components: doc.Components.Schemas}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("openapi schema not found for %s", gvk.String())
+}
+
+// Resolve follows the references of the schema until it reaches a schema defining its type; a schema made up of a
+// single `allOf` entry, as used for fields with defaults, is treated as a reference. A nil schema is returned if a
+// reference can't be resolved.
+func (s *OpenAPISchema) Resolve(sc *spec.Schema) *spec.Schema {
+	for range maxRefDepth {
+		if sc == nil {
+			return nil
+		}
+
+		if ref := sc.Ref.String(); len(ref) > 0 {
+			if !strings.HasPrefix(ref, componentSchemaRefPrefix) {
+				return nil
+			}
+
+			sc = s.Components[strings.TrimPrefix(ref, componentSchemaRefPrefix)]
+			continue
+		}
+
+		if len(sc.AllOf) == 1 && len(sc.Type) == 0 && len(sc.Properties) == 0 {
+			sc = &sc.AllOf[0]
+			continue
+		}
+
+		return sc
+	}
+
+	return nil
+}
+
+// hasGroupVersionKind returns true if the schema is tagged with the given GroupVersionKind.
+func hasGroupVersionKind(s *spec.Schema, gvk schema.GroupVersionKind) bool {
+	gvks, ok := s.Extensions["x-kubernetes-group-version-kind"].([]any)
+	if !ok {
+		return false
+	}
+
+	for _, v := range gvks {
+		m, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if m["group"] == gvk.Group && m["version"] == gvk.Version && m["kind"] == gvk.Kind {
+			return true
+		}
+	}
+
+	return false
+}
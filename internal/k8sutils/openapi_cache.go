@@ -0,0 +1,105 @@
+package k8sutils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/client-go/openapi"
+)
+
+// NewDiskCachedOpenAPIClient returns an OpenAPI v3 client which caches the group version documents in the given
+// directory; documents are keyed by their server relative URL, which contains a hash of the document, so updated
+// documents are fetched again. Failing to write to the cache doesn't fail the request.
+func NewDiskCachedOpenAPIClient(c openapi.Client, dir string) openapi.Client {
+	return &diskCachedOpenAPIClient{
+		delegate: c,
+		dir:      dir,
+	}
+}
+
+// diskCachedOpenAPIClient is an OpenAPI v3 client caching group version documents on disk.
+type diskCachedOpenAPIClient struct {
+	delegate openapi.Client
+	dir      string
+}
+
+// Paths returns the group versions of the server, with their documents cached on disk.
+func (c *diskCachedOpenAPIClient) Paths() (map[string]openapi.GroupVersion, error) {
+	paths, err := c.delegate.Paths()
+	if err != nil {
+		return nil, err
+	}
+
+	cached := make(map[string]openapi.GroupVersion, len(paths))
+	for k, gv := range paths {
+		cached[k] = &diskCachedGroupVersion{
+			delegate: gv,
+			dir:      c.dir,
+		}
+	}
+
+	return cached, nil
+}
+
+// diskCachedGroupVersion is an OpenAPI v3 group version with its document cached on disk.
+type diskCachedGroupVersion struct {
+	delegate openapi.GroupVersion
+	dir      string
+}
+
+// Schema returns the document of the group version in the given content type, reading it from the cache if present.
+func (g *diskCachedGroupVersion) Schema(contentType string) ([]byte, error) {
+	file := filepath.Join(g.dir, cacheFileName(g.delegate.ServerRelativeURL(), contentType))
+
+	if b, err := os.ReadFile(file); err == nil {
+		return b, nil
+	}
+
+	b, err := g.delegate.Schema(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = writeCacheFile(file, b)
+
+	return b, nil
+}
+
+// ServerRelativeURL returns the server relative URL of the group version document.
+func (g *diskCachedGroupVersion) ServerRelativeURL() string {
+	return g.delegate.ServerRelativeURL()
+}
+
+// cacheFileName returns the cache file name for a document URL and content type.
+func cacheFileName(url, contentType string) string {
+	h := sha256.Sum256(fmt.Appendf(nil, "%s\n%s", url, contentType))
+	return hex.EncodeToString(h[:])
+}
+
+// writeCacheFile writes the data to the file, replacing it atomically so concurrent readers never see a partial file.
+func writeCacheFile(file string, b []byte) error {
+	dir := filepath.Dir(file)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), file)
+}
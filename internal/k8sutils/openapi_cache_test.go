@@ -0,0 +1,112 @@
+package k8sutils
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/client-go/openapi"
+	"k8s.io/client-go/openapi/openapitest"
+)
+
+type countingOpenAPIClient struct {
+	delegate openapi.Client
+	count    int
+}
+
+func (c *countingOpenAPIClient) Paths() (map[string]openapi.GroupVersion, error) {
+	paths, err := c.delegate.Paths()
+	if err != nil {
+		return nil, err
+	}
+
+	counted := make(map[string]openapi.GroupVersion, len(paths))
+	for k, gv := range paths {
+		counted[k] = &countingGroupVersion{GroupVersion: gv, count: &c.count}
+	}
+
+	return counted, nil
+}
+
+type countingGroupVersion struct {
+	openapi.GroupVersion
+
+	count *int
+}
+
+func (g *countingGroupVersion) Schema(contentType string) ([]byte, error) {
+	*g.count++
+	return g.GroupVersion.Schema(contentType)
+}
+
+func TestDiskCachedOpenAPIClient(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	delegate := &countingOpenAPIClient{delegate: openapitest.NewEmbeddedFileClient()}
+
+	var want []byte
+	for i := range 2 {
+		paths, err := NewDiskCachedOpenAPIClient(delegate, dir).Paths()
+		if err != nil {
+			t.Fatalf("Paths() returned unexpected error: %v", err)
+		}
+
+		gv, ok := paths["apis/apps/v1"]
+		if !ok {
+			t.Fatalf("Paths() didn't return apis/apps/v1")
+		}
+
+		got, err := gv.Schema("application/json")
+		if err != nil {
+			t.Fatalf("Schema() returned unexpected error: %v", err)
+		}
+
+		if i == 0 {
+			want = got
+			continue
+		}
+
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Schema() mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	if delegate.count != 1 {
+		t.Errorf("Schema() fetched the document %d times, want 1", delegate.count)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() returned unexpected error: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("cache directory contains %d entries, want 1", len(entries))
+	}
+}
+
+func TestDiskCachedOpenAPIClientError(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	paths, err := NewDiskCachedOpenAPIClient(openapitest.NewEmbeddedFileClient(), dir).Paths()
+	if err != nil {
+		t.Fatalf("Paths() returned unexpected error: %v", err)
+	}
+
+	if _, err := paths["apis/apps/v1"].Schema("application/yaml"); err == nil {
+		t.Errorf("Schema() returned no error, want an error")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() returned unexpected error: %v", err)
+	}
+
+	if len(entries) != 0 {
+		t.Errorf("cache directory contains %d entries, want 0", len(entries))
+	}
+}
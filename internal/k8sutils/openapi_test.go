@@ -0,0 +1,190 @@
+package k8sutils
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/openapi/openapitest"
+	"k8s.io/client-go/openapi3"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestNewOpenAPISchema(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []struct {
+		testName       string
+		doc            *spec3.OpenAPI
+		gvk            schema.GroupVersionKind
+		wantProperties []string
+		wantErr        *string
+	}{
+		{
+			testName:       "core",
+			doc:            testOpenAPIDocument(t, schema.GroupVersion{Version: "v1"}),
+			gvk:            schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			wantProperties: []string{"apiVersion", "binaryData", "data", "immutable", "kind", "metadata"},
+		},
+		{
+			testName:       "group",
+			doc:            testOpenAPIDocument(t, schema.GroupVersion{Group: "apps", Version: "v1"}),
+			gvk:            schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			wantProperties: []string{"apiVersion", "kind", "metadata", "spec", "status"},
+		},
+		{
+			testName: "unknown_kind",
+			doc:      testOpenAPIDocument(t, schema.GroupVersion{Group: "apps", Version: "v1"}),
+			gvk:      schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Foo"},
+			wantErr:  new("openapi schema not found for apps/v1, Kind=Foo"),
+		},
+		{
+			testName: "no_document",
+			gvk:      schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Foo"},
+			wantErr:  new("openapi document has no component schemas"),
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewOpenAPISchema(d.doc, d.gvk)
+			if err != nil {
+				if d.wantErr == nil {
+					t.Errorf("NewOpenAPISchema() returned unexpected error: %v", err)
+				}
+
+				if !regexp.MustCompile(regexp.QuoteMeta(*d.wantErr)).MatchString(err.Error()) {
+					t.Errorf("NewOpenAPISchema() returned error %q, want %q", err.Error(), *d.wantErr)
+				}
+
+				return
+			}
+
+			if d.wantErr != nil {
+				t.Errorf("NewOpenAPISchema() returned no error, want %q", *d.wantErr)
+			}
+
+			props := make([]string, 0, len(got.Schema.Properties))
+			for k := range got.Schema.Properties {
+				props = append(props, k)
+			}
+
+			slices.Sort(props)
+
+			if diff := cmp.Diff(d.wantProperties, props); diff != "" {
+				t.Errorf("NewOpenAPISchema() properties mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOpenAPISchemaResolve(t *testing.T) {
+	t.Parallel()
+
+	s := testOpenAPISchema(t, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+
+	for _, d := range []struct {
+		testName string
+		path     []string
+		wantType []string
+	}{
+		{
+			testName: "all_of_ref",
+			path:     []string{"spec"},
+			wantType: []string{"object"},
+		},
+		{
+			testName: "nested_ref",
+			path:     []string{"spec", "template", "spec"},
+			wantType: []string{"object"},
+		},
+		{
+			testName: "scalar",
+			path:     []string{"spec", "replicas"},
+			wantType: []string{"integer"},
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			sc := s.Schema
+			for _, p := range d.path {
+				prop := s.Resolve(sc).Properties[p]
+				sc = &prop
+			}
+
+			got := s.Resolve(sc)
+			if got == nil {
+				t.Fatalf("Resolve() returned nil")
+			}
+
+			if diff := cmp.Diff(d.wantType, []string(got.Type)); diff != "" {
+				t.Errorf("Resolve() type mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOpenAPISchemaResolveMissingRef(t *testing.T) {
+	t.Parallel()
+
+	s := &OpenAPISchema{Components: map[string]*spec.Schema{"foo": spec.RefSchema("#/components/schemas/foo")}}
+
+	for _, d := range []struct {
+		testName string
+		in       *spec.Schema
+	}{
+		{
+			testName: "nil",
+			in:       nil,
+		},
+		{
+			testName: "missing_ref",
+			in:       spec.RefSchema("#/components/schemas/missing"),
+		},
+		{
+			testName: "external_ref",
+			in:       spec.RefSchema("https://example.com/schema.json"),
+		},
+		{
+			testName: "circular_ref",
+			in:       spec.RefSchema("#/components/schemas/foo"),
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := s.Resolve(d.in); got != nil {
+				t.Errorf("Resolve() returned %v, want nil", got)
+			}
+		})
+	}
+}
+
+// testOpenAPIDocument returns the OpenAPI v3 document of the group version from the embedded test documents.
+func testOpenAPIDocument(t *testing.T, gv schema.GroupVersion) *spec3.OpenAPI {
+	t.Helper()
+
+	doc, err := openapi3.NewRoot(openapitest.NewEmbeddedFileClient()).GVSpec(gv)
+	if err != nil {
+		t.Fatalf("failed to get openapi document for %s: %v", gv.String(), err)
+	}
+
+	return doc
+}
+
+// testOpenAPISchema returns the OpenAPI v3 schema of the kind from the embedded test documents.
+func testOpenAPISchema(t *testing.T, gvk schema.GroupVersionKind) *OpenAPISchema {
+	t.Helper()
+
+	s, err := NewOpenAPISchema(testOpenAPIDocument(t, gvk.GroupVersion()), gvk)
+	if err != nil {
+		t.Fatalf("NewOpenAPISchema() returned unexpected error: %v", err)
+	}
+
+	return s
+}
@@ -0,0 +1,144 @@
+package k8sutils
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+// RetryOptions configures retrying API requests.
+type RetryOptions struct {
+	// MaxAttempts is the maximum number of attempts made for a request, including the first one.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry; the delay is doubled for each retry after that.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum delay between attempts, including delays requested with a `Retry-After` header.
+	MaxBackoff time.Duration
+}
+
+// NewRetryRoundTripper returns a round tripper which retries requests that fail with a 429 status and idempotent
+// requests that fail with a connection reset, an unexpected EOF or a 5xx status; the delay between attempts is taken
+// from the `Retry-After` header if present, otherwise it's backed off exponentially. Requests with a body which can't be
+// replayed aren't retried. The client-go REST client also retries `429` and `5xx` responses with a `Retry-After` header,
+// up to 10 times by default, so for those responses the total number of attempts is a multiple of MaxAttempts.
+func NewRetryRoundTripper(rt http.RoundTripper, opts RetryOptions) http.RoundTripper {
+	return &retryRoundTripper{
+		delegate: rt,
+		opts:     opts,
+	}
+}
+
+// retryRoundTripper is a round tripper retrying failed requests.
+type retryRoundTripper struct {
+	delegate http.RoundTripper
+	opts     RetryOptions
+}
+
+// RoundTrip executes the request, retrying it if it fails with a retryable error.
+func (t *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.delegate.RoundTrip(req)
+		if attempt >= t.opts.MaxAttempts || !isRetryable(req, resp, err) || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt, resp)
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// backoff returns the delay before the next attempt.
+func (t *retryRoundTripper) backoff(attempt int, resp *http.Response) time.Duration {
+	delay, ok := retryAfter(resp)
+	if !ok {
+		delay = time.Duration(float64(t.opts.MinBackoff) * math.Pow(2, float64(attempt-1)))
+	}
+
+	if t.opts.MaxBackoff > 0 && delay > t.opts.MaxBackoff {
+		return t.opts.MaxBackoff
+	}
+
+	return delay
+}
+
+// isRetryable returns true if the request failed with a 429 status, or if it's idempotent and failed with a connection
+// reset, an unexpected EOF or a 5xx status; a 429 means the request wasn't processed so any request can be retried.
+func isRetryable(req *http.Request, resp *http.Response, err error) bool {
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if !isIdempotent(req) {
+		return false
+	}
+
+	if err != nil {
+		return utilnet.IsConnectionReset(err) || utilnet.IsProbableEOF(err)
+	}
+
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// isIdempotent returns true if repeating the request has the same effect as making it once; this is the case for GET,
+// HEAD and PUT requests, and for PATCH requests using server-side apply.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut:
+		return true
+	case http.MethodPatch:
+		ct := types.PatchType(req.Header.Get("Content-Type"))
+		return ct == types.ApplyYAMLPatchType || ct == types.ApplyCBORPatchType
+	default:
+		return false
+	}
+}
+
+// retryAfter returns the delay requested by the `Retry-After` header of the response; this supports both delay seconds
+// and HTTP dates.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	v := resp.Header.Get("Retry-After")
+	if len(v) == 0 {
+		return 0, false
+	}
+
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+
+	if d, err := http.ParseTime(v); err == nil {
+		return max(time.Until(d), 0), true
+	}
+
+	return 0, false
+}
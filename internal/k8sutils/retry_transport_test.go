@@ -0,0 +1,233 @@
+package k8sutils
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryRoundTripper(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []struct {
+		testName     string
+		method       string
+		contentType  string
+		statuses     []int
+		retryAfter   string
+		maxAttempts  int
+		body         io.Reader
+		wantStatus   int
+		wantAttempts int32
+	}{
+		{
+			testName:     "success",
+			statuses:     []int{http.StatusOK},
+			maxAttempts:  3,
+			wantStatus:   http.StatusOK,
+			wantAttempts: 1,
+		},
+		{
+			testName:     "retry_too_many_requests",
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:   "0",
+			maxAttempts:  3,
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+		},
+		{
+			testName:     "retry_server_error",
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			maxAttempts:  3,
+			wantStatus:   http.StatusOK,
+			wantAttempts: 3,
+		},
+		{
+			testName:     "max_attempts",
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			maxAttempts:  2,
+			wantStatus:   http.StatusServiceUnavailable,
+			wantAttempts: 2,
+		},
+		{
+			testName:     "not_retryable",
+			statuses:     []int{http.StatusNotFound, http.StatusOK},
+			maxAttempts:  3,
+			wantStatus:   http.StatusNotFound,
+			wantAttempts: 1,
+		},
+		{
+			testName:     "post_too_many_requests",
+			method:       http.MethodPost,
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:   "0",
+			maxAttempts:  3,
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+		},
+		{
+			testName:     "post_server_error",
+			method:       http.MethodPost,
+			statuses:     []int{http.StatusInternalServerError, http.StatusOK},
+			maxAttempts:  3,
+			wantStatus:   http.StatusInternalServerError,
+			wantAttempts: 1,
+		},
+		{
+			testName:     "apply_patch_server_error",
+			method:       http.MethodPatch,
+			contentType:  "application/apply-patch+yaml",
+			statuses:     []int{http.StatusInternalServerError, http.StatusOK},
+			maxAttempts:  3,
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+		},
+		{
+			testName:     "merge_patch_server_error",
+			method:       http.MethodPatch,
+			contentType:  "application/merge-patch+json",
+			statuses:     []int{http.StatusInternalServerError, http.StatusOK},
+			maxAttempts:  3,
+			wantStatus:   http.StatusInternalServerError,
+			wantAttempts: 1,
+		},
+		{
+			testName:     "replay_body",
+			method:       http.MethodPut,
+			statuses:     []int{http.StatusInternalServerError, http.StatusOK},
+			maxAttempts:  3,
+			body:         bytes.NewReader([]byte("foo")),
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+		},
+		{
+			testName:     "body_not_replayable",
+			method:       http.MethodPut,
+			statuses:     []int{http.StatusInternalServerError, http.StatusOK},
+			maxAttempts:  3,
+			body:         io.NopCloser(bytes.NewReader([]byte("foo"))),
+			wantStatus:   http.StatusInternalServerError,
+			wantAttempts: 1,
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := attempts.Add(1)
+
+				if d.body != nil {
+					if b, _ := io.ReadAll(r.Body); string(b) != "foo" {
+						t.Errorf("request %d has body %q, want %q", n, string(b), "foo")
+					}
+				}
+
+				if len(d.retryAfter) > 0 {
+					w.Header().Set("Retry-After", d.retryAfter)
+				}
+
+				w.WriteHeader(d.statuses[n-1])
+			}))
+			defer srv.Close()
+
+			client := &http.Client{Transport: NewRetryRoundTripper(http.DefaultTransport, RetryOptions{
+				MaxAttempts: d.maxAttempts,
+				MinBackoff:  time.Millisecond,
+				MaxBackoff:  10 * time.Millisecond,
+			})}
+
+			method := d.method
+			if len(method) == 0 {
+				method = http.MethodGet
+			}
+
+			req, err := http.NewRequestWithContext(t.Context(), method, srv.URL, d.body)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			if len(d.contentType) > 0 {
+				req.Header.Set("Content-Type", d.contentType)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() returned unexpected error: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != d.wantStatus {
+				t.Errorf("RoundTrip() returned status %d, want %d", resp.StatusCode, d.wantStatus)
+			}
+
+			if got := attempts.Load(); got != d.wantAttempts {
+				t.Errorf("RoundTrip() made %d attempts, want %d", got, d.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryRoundTripperBackoff(t *testing.T) {
+	t.Parallel()
+
+	rt := &retryRoundTripper{opts: RetryOptions{MinBackoff: time.Second, MaxBackoff: 10 * time.Second}}
+
+	for _, d := range []struct {
+		testName   string
+		attempt    int
+		retryAfter string
+		want       time.Duration
+	}{
+		{
+			testName: "first_retry",
+			attempt:  1,
+			want:     time.Second,
+		},
+		{
+			testName: "exponential",
+			attempt:  3,
+			want:     4 * time.Second,
+		},
+		{
+			testName: "max_backoff",
+			attempt:  10,
+			want:     10 * time.Second,
+		},
+		{
+			testName:   "retry_after",
+			attempt:    1,
+			retryAfter: "5",
+			want:       5 * time.Second,
+		},
+		{
+			testName:   "retry_after_max_backoff",
+			attempt:    1,
+			retryAfter: "60",
+			want:       10 * time.Second,
+		},
+		{
+			testName:   "retry_after_invalid",
+			attempt:    2,
+			retryAfter: "foo",
+			want:       2 * time.Second,
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &http.Response{Header: http.Header{}}
+			if len(d.retryAfter) > 0 {
+				resp.Header.Set("Retry-After", d.retryAfter)
+			}
+
+			if got := rt.backoff(d.attempt, resp); got != d.want {
+				t.Errorf("backoff() returned %s, want %s", got, d.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package k8sutils
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// volatileFieldPaths are the paths of the object fields set by the API server which change independently of the
+// applied configuration; `status` is handled separately as it can be opted into.
+var volatileFieldPaths = []string{
+	"metadata.creationTimestamp",
+	"metadata.generation",
+	"metadata.resourceVersion",
+	"metadata.selfLink",
+	"metadata.managedFields[*].time",
+}
+
+// SanitizeOptions configures how an object is sanitized.
+type SanitizeOptions struct {
+	// IncludeStatus keeps the `status` field of the object.
+	IncludeStatus bool
+	// ExcludePaths are additional field paths to remove from the object; see RemoveFieldPath for the path syntax.
+	ExcludePaths []string
+}
+
+// SanitizeObject returns a copy of the object without the volatile fields set by the API server and the additional
+// excluded paths; the object passed in isn't modified.
+func SanitizeObject(obj map[string]any, opts SanitizeOptions) (map[string]any, error) {
+	if obj == nil {
+		return nil, nil
+	}
+
+	o := runtime.DeepCopyJSON(obj)
+
+	if !opts.IncludeStatus {
+		delete(o, "status")
+	}
+
+	for _, p := range volatileFieldPaths {
+		if err := RemoveFieldPath(o, p); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, p := range opts.ExcludePaths {
+		if err := RemoveFieldPath(o, p); err != nil {
+			return nil, fmt.Errorf("failed to exclude path: %w", err)
+		}
+	}
+
+	return o, nil
+}
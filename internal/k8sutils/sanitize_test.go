@@ -0,0 +1,123 @@
+package k8sutils
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSanitizeObject(t *testing.T) {
+	t.Parallel()
+
+	newObj := func() map[string]any {
+		return map[string]any{
+			"metadata": map[string]any{
+				"name":              "foo",
+				"creationTimestamp": "2025-01-01T00:00:00Z",
+				"generation":        int64(2),
+				"resourceVersion":   "1234",
+				"annotations":       map[string]any{"example.com/foo": "bar"},
+				"managedFields": []any{
+					map[string]any{"manager": "test", "operation": "Apply", "time": "2025-01-01T00:00:00Z"},
+				},
+			},
+			"spec":   map[string]any{"replicas": int64(1)},
+			"status": map[string]any{"replicas": int64(1)},
+		}
+	}
+
+	for _, d := range []struct {
+		testName string
+		in       map[string]any
+		opts     SanitizeOptions
+		want     map[string]any
+		wantErr  *string
+	}{
+		{
+			testName: "nil",
+			in:       nil,
+			want:     nil,
+		},
+		{
+			testName: "defaults",
+			in:       newObj(),
+			want: map[string]any{
+				"metadata": map[string]any{
+					"name":        "foo",
+					"annotations": map[string]any{"example.com/foo": "bar"},
+					"managedFields": []any{
+						map[string]any{"manager": "test", "operation": "Apply"},
+					},
+				},
+				"spec": map[string]any{"replicas": int64(1)},
+			},
+		},
+		{
+			testName: "include_status",
+			in:       newObj(),
+			opts:     SanitizeOptions{IncludeStatus: true},
+			want: map[string]any{
+				"metadata": map[string]any{
+					"name":        "foo",
+					"annotations": map[string]any{"example.com/foo": "bar"},
+					"managedFields": []any{
+						map[string]any{"manager": "test", "operation": "Apply"},
+					},
+				},
+				"spec":   map[string]any{"replicas": int64(1)},
+				"status": map[string]any{"replicas": int64(1)},
+			},
+		},
+		{
+			testName: "exclude_paths",
+			in:       newObj(),
+			opts:     SanitizeOptions{ExcludePaths: []string{"metadata.managedFields", `metadata.annotations["example.com/foo"]`}},
+			want: map[string]any{
+				"metadata": map[string]any{
+					"name":        "foo",
+					"annotations": map[string]any{},
+				},
+				"spec": map[string]any{"replicas": int64(1)},
+			},
+		},
+		{
+			testName: "invalid_exclude_path",
+			in:       newObj(),
+			opts:     SanitizeOptions{ExcludePaths: []string{"spec..replicas"}},
+			wantErr:  new(`failed to exclude path: invalid field path "spec..replicas": unexpected dot`),
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			in := newObj()
+			got, err := SanitizeObject(d.in, d.opts)
+			if err != nil {
+				if d.wantErr == nil {
+					t.Errorf("SanitizeObject() returned unexpected error: %v", err)
+				}
+
+				if !regexp.MustCompile(regexp.QuoteMeta(*d.wantErr)).MatchString(err.Error()) {
+					t.Errorf("SanitizeObject() returned error %q, want %q", err.Error(), *d.wantErr)
+				}
+
+				return
+			}
+
+			if d.wantErr != nil {
+				t.Errorf("SanitizeObject() returned no error, want %q", *d.wantErr)
+			}
+
+			if diff := cmp.Diff(d.want, got); diff != "" {
+				t.Errorf("SanitizeObject() mismatch (-want +got):\n%s", diff)
+			}
+
+			if d.in != nil {
+				if diff := cmp.Diff(in, d.in); diff != "" {
+					t.Errorf("SanitizeObject() modified the input (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package k8sutils
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Status is the readiness status of an object.
+type Status string
+
+const (
+	// StatusInProgress is the status of an object which hasn't reached its desired state yet.
+	StatusInProgress Status = "InProgress"
+	// StatusCurrent is the status of an object which has reached its desired state.
+	StatusCurrent Status = "Current"
+	// StatusFailed is the status of an object which failed to reach its desired state.
+	StatusFailed Status = "Failed"
+	// StatusTerminating is the status of an object which is being deleted.
+	StatusTerminating Status = "Terminating"
+)
+
+// ReadyStatus is the readiness status of an object with the reason for it.
+type ReadyStatus struct {
+	Status Status
+	Reason string
+}
+
+// ComputeReadyStatus computes the readiness status of the object; deployments, stateful sets, daemon sets, jobs, pods,
+// persistent volume claims and services have their kind specific status checked, all other objects are evaluated using
+// the standard `Ready`, `Reconciling` and `Stalled` conditions.
+func ComputeReadyStatus(obj *unstructured.Unstructured) ReadyStatus {
+	if obj.GetDeletionTimestamp() != nil {
+		return ReadyStatus{Status: StatusTerminating, Reason: "resource scheduled for deletion"}
+	}
+
+	group := obj.GroupVersionKind().Group
+	switch {
+	case group == "apps" && obj.GetKind() == "Deployment":
+		return deploymentReadyStatus(obj)
+	case group == "apps" && (obj.GetKind() == "StatefulSet" || obj.GetKind() == "DaemonSet"), group == "batch" && obj.GetKind() == "Job":
+		return rolloutReadyStatus(obj)
+	case len(group) == 0 && obj.GetKind() == "Pod":
+		return podReadyStatus(obj)
+	case len(group) == 0 && obj.GetKind() == "PersistentVolumeClaim":
+		return pvcReadyStatus(obj)
+	case len(group) == 0 && obj.GetKind() == "Service":
+		return serviceReadyStatus(obj)
+	default:
+		return genericReadyStatus(obj)
+	}
+}
+
+// deploymentReadyStatus computes the readiness status of a deployment.
+func deploymentReadyStatus(obj *unstructured.Unstructured) ReadyStatus {
+	if status, ok := getCondition(obj, "Progressing"); ok && status["reason"] == "ProgressDeadlineExceeded" {
+		return ReadyStatus{Status: StatusFailed, Reason: conditionMessage(status, "progress deadline exceeded")}
+	}
+
+	return rolloutReadyStatus(obj)
+}
+
+// rolloutReadyStatus computes the readiness status of an object from its rollout status.
+func rolloutReadyStatus(obj *unstructured.Unstructured) ReadyStatus {
+	done, msg, err := RolloutComplete(obj)
+	switch {
+	case err != nil:
+		return ReadyStatus{Status: StatusFailed, Reason: err.Error()}
+	case !done:
+		return ReadyStatus{Status: StatusInProgress, Reason: msg}
+	default:
+		return ReadyStatus{Status: StatusCurrent, Reason: "rollout complete"}
+	}
+}
+
+// podReadyStatus computes the readiness status of a pod.
+func podReadyStatus(obj *unstructured.Unstructured) ReadyStatus {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	switch phase {
+	case "Succeeded":
+		return ReadyStatus{Status: StatusCurrent, Reason: "pod succeeded"}
+	case "Failed":
+		return ReadyStatus{Status: StatusFailed, Reason: "pod failed"}
+	}
+
+	statuses, _, _ := unstructured.NestedSlice(obj.Object, "status", "containerStatuses")
+	for _, s := range statuses {
+		reason, _, _ := unstructured.NestedString(asMap(s), "state", "waiting", "reason")
+		if reason == "CrashLoopBackOff" || reason == "ImagePullBackOff" || reason == "ErrImagePull" {
+			name, _, _ := unstructured.NestedString(asMap(s), "name")
+			return ReadyStatus{Status: StatusFailed, Reason: fmt.Sprintf("container %q is in %s", name, reason)}
+		}
+	}
+
+	if status, ok := getConditionStatus(obj, "Ready"); ok && status == "True" {
+		return ReadyStatus{Status: StatusCurrent, Reason: "pod ready"}
+	}
+
+	if len(phase) == 0 {
+		return ReadyStatus{Status: StatusInProgress, Reason: "pod not ready"}
+	}
+
+	return ReadyStatus{Status: StatusInProgress, Reason: fmt.Sprintf("pod %s and not ready", phase)}
+}
+
+// pvcReadyStatus computes the readiness status of a persistent volume claim.
+func pvcReadyStatus(obj *unstructured.Unstructured) ReadyStatus {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	switch phase {
+	case "Bound":
+		return ReadyStatus{Status: StatusCurrent, Reason: "persistent volume claim bound"}
+	case "Lost":
+		return ReadyStatus{Status: StatusFailed, Reason: "persistent volume claim lost"}
+	default:
+		return ReadyStatus{Status: StatusInProgress, Reason: "persistent volume claim not bound"}
+	}
+}
+
+// serviceReadyStatus computes the readiness status of a service; only load balancer services need to be provisioned.
+func serviceReadyStatus(obj *unstructured.Unstructured) ReadyStatus {
+	serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type")
+	if serviceType != "LoadBalancer" {
+		return ReadyStatus{Status: StatusCurrent, Reason: "service ready"}
+	}
+
+	ingress, _, _ := unstructured.NestedSlice(obj.Object, "status", "loadBalancer", "ingress")
+	if len(ingress) == 0 {
+		return ReadyStatus{Status: StatusInProgress, Reason: "load balancer not provisioned"}
+	}
+
+	return ReadyStatus{Status: StatusCurrent, Reason: "load balancer provisioned"}
+}
+
+// genericReadyStatus computes the readiness status of an object from its observed generation and standard conditions.
+func genericReadyStatus(obj *unstructured.Unstructured) ReadyStatus {
+	if done, msg, _ := RolloutComplete(obj); !done {
+		return ReadyStatus{Status: StatusInProgress, Reason: msg}
+	}
+
+	if status, ok := getCondition(obj, "Stalled"); ok && status["status"] == "True" {
+		return ReadyStatus{Status: StatusFailed, Reason: conditionMessage(status, "resource stalled")}
+	}
+
+	if status, ok := getCondition(obj, "Reconciling"); ok && status["status"] == "True" {
+		return ReadyStatus{Status: StatusInProgress, Reason: conditionMessage(status, "resource reconciling")}
+	}
+
+	status, ok := getCondition(obj, "Ready")
+	if !ok {
+		return ReadyStatus{Status: StatusCurrent, Reason: "resource current"}
+	}
+
+	if status["status"] != "True" {
+		return ReadyStatus{Status: StatusInProgress, Reason: conditionMessage(status, "resource not ready")}
+	}
+
+	return ReadyStatus{Status: StatusCurrent, Reason: conditionMessage(status, "resource ready")}
+}
+
+// getCondition returns the `status.conditions` item with the given type.
+func getCondition(obj *unstructured.Unstructured, conditionType string) (map[string]any, bool) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		m := asMap(c)
+		if m["type"] == conditionType {
+			return m, true
+		}
+	}
+
+	return nil, false
+}
+
+// conditionMessage returns the message of the condition, or the default if it doesn't have one.
+func conditionMessage(condition map[string]any, def string) string {
+	if msg, ok := condition["message"].(string); ok && len(msg) > 0 {
+		return msg
+	}
+
+	return def
+}
+
+// asMap returns the value as a map, or nil if it isn't one.
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
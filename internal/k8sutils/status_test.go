@@ -0,0 +1,250 @@
+package k8sutils
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestComputeReadyStatus(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []struct {
+		testName string
+		obj      map[string]any
+		want     ReadyStatus
+	}{
+		{
+			testName: "terminating",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]any{"name": "foo", "deletionTimestamp": "2024-01-01T00:00:00Z"},
+			},
+			want: ReadyStatus{Status: StatusTerminating, Reason: "resource scheduled for deletion"},
+		},
+		{
+			testName: "config_map",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]any{"name": "foo"},
+			},
+			want: ReadyStatus{Status: StatusCurrent, Reason: "resource current"},
+		},
+		{
+			testName: "deployment_current",
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "foo", "generation": int64(1)},
+				"status":     map[string]any{"observedGeneration": int64(1), "replicas": int64(1), "updatedReplicas": int64(1), "availableReplicas": int64(1)},
+			},
+			want: ReadyStatus{Status: StatusCurrent, Reason: "rollout complete"},
+		},
+		{
+			testName: "deployment_in_progress",
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "foo", "generation": int64(2)},
+				"status":     map[string]any{"observedGeneration": int64(1)},
+			},
+			want: ReadyStatus{Status: StatusInProgress, Reason: "observed generation 1 is behind generation 2"},
+		},
+		{
+			testName: "deployment_failed",
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "foo", "generation": int64(1)},
+				"status": map[string]any{
+					"observedGeneration": int64(1),
+					"conditions": []any{
+						map[string]any{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded", "message": "ReplicaSet \"foo-123\" has timed out progressing."},
+					},
+				},
+			},
+			want: ReadyStatus{Status: StatusFailed, Reason: "ReplicaSet \"foo-123\" has timed out progressing."},
+		},
+		{
+			testName: "stateful_set_in_progress",
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "StatefulSet",
+				"metadata":   map[string]any{"name": "foo", "generation": int64(1)},
+				"spec":       map[string]any{"replicas": int64(3)},
+				"status":     map[string]any{"observedGeneration": int64(1), "readyReplicas": int64(2)},
+			},
+			want: ReadyStatus{Status: StatusInProgress, Reason: "2 of 3 replicas ready"},
+		},
+		{
+			testName: "daemon_set_current",
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "DaemonSet",
+				"metadata":   map[string]any{"name": "foo", "generation": int64(1)},
+				"status":     map[string]any{"observedGeneration": int64(1), "desiredNumberScheduled": int64(2), "updatedNumberScheduled": int64(2), "numberAvailable": int64(2)},
+			},
+			want: ReadyStatus{Status: StatusCurrent, Reason: "rollout complete"},
+		},
+		{
+			testName: "job_failed",
+			obj: map[string]any{
+				"apiVersion": "batch/v1",
+				"kind":       "Job",
+				"metadata":   map[string]any{"name": "foo"},
+				"status":     map[string]any{"conditions": []any{map[string]any{"type": "Failed", "status": "True"}}},
+			},
+			want: ReadyStatus{Status: StatusFailed, Reason: "job foo failed"},
+		},
+		{
+			testName: "pod_ready",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata":   map[string]any{"name": "foo"},
+				"status":     map[string]any{"phase": "Running", "conditions": []any{map[string]any{"type": "Ready", "status": "True"}}},
+			},
+			want: ReadyStatus{Status: StatusCurrent, Reason: "pod ready"},
+		},
+		{
+			testName: "pod_pending",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata":   map[string]any{"name": "foo"},
+				"status":     map[string]any{"phase": "Pending"},
+			},
+			want: ReadyStatus{Status: StatusInProgress, Reason: "pod Pending and not ready"},
+		},
+		{
+			testName: "pod_crash_loop",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata":   map[string]any{"name": "foo"},
+				"status": map[string]any{
+					"phase": "Running",
+					"containerStatuses": []any{
+						map[string]any{"name": "bar", "state": map[string]any{"waiting": map[string]any{"reason": "CrashLoopBackOff"}}},
+					},
+				},
+			},
+			want: ReadyStatus{Status: StatusFailed, Reason: `container "bar" is in CrashLoopBackOff`},
+		},
+		{
+			testName: "pod_succeeded",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata":   map[string]any{"name": "foo"},
+				"status":     map[string]any{"phase": "Succeeded"},
+			},
+			want: ReadyStatus{Status: StatusCurrent, Reason: "pod succeeded"},
+		},
+		{
+			testName: "pvc_bound",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "PersistentVolumeClaim",
+				"metadata":   map[string]any{"name": "foo"},
+				"status":     map[string]any{"phase": "Bound"},
+			},
+			want: ReadyStatus{Status: StatusCurrent, Reason: "persistent volume claim bound"},
+		},
+		{
+			testName: "pvc_pending",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "PersistentVolumeClaim",
+				"metadata":   map[string]any{"name": "foo"},
+				"status":     map[string]any{"phase": "Pending"},
+			},
+			want: ReadyStatus{Status: StatusInProgress, Reason: "persistent volume claim not bound"},
+		},
+		{
+			testName: "service_cluster_ip",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata":   map[string]any{"name": "foo"},
+				"spec":       map[string]any{"type": "ClusterIP"},
+			},
+			want: ReadyStatus{Status: StatusCurrent, Reason: "service ready"},
+		},
+		{
+			testName: "service_load_balancer_pending",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata":   map[string]any{"name": "foo"},
+				"spec":       map[string]any{"type": "LoadBalancer"},
+			},
+			want: ReadyStatus{Status: StatusInProgress, Reason: "load balancer not provisioned"},
+		},
+		{
+			testName: "service_load_balancer_provisioned",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata":   map[string]any{"name": "foo"},
+				"spec":       map[string]any{"type": "LoadBalancer"},
+				"status":     map[string]any{"loadBalancer": map[string]any{"ingress": []any{map[string]any{"ip": "10.0.0.1"}}}},
+			},
+			want: ReadyStatus{Status: StatusCurrent, Reason: "load balancer provisioned"},
+		},
+		{
+			testName: "custom_resource_ready",
+			obj: map[string]any{
+				"apiVersion": "example.com/v1",
+				"kind":       "Foo",
+				"metadata":   map[string]any{"name": "foo", "generation": int64(1)},
+				"status":     map[string]any{"observedGeneration": int64(1), "conditions": []any{map[string]any{"type": "Ready", "status": "True"}}},
+			},
+			want: ReadyStatus{Status: StatusCurrent, Reason: "resource ready"},
+		},
+		{
+			testName: "custom_resource_not_ready",
+			obj: map[string]any{
+				"apiVersion": "example.com/v1",
+				"kind":       "Foo",
+				"metadata":   map[string]any{"name": "foo"},
+				"status":     map[string]any{"conditions": []any{map[string]any{"type": "Ready", "status": "False", "message": "waiting for bar"}}},
+			},
+			want: ReadyStatus{Status: StatusInProgress, Reason: "waiting for bar"},
+		},
+		{
+			testName: "custom_resource_stalled",
+			obj: map[string]any{
+				"apiVersion": "example.com/v1",
+				"kind":       "Foo",
+				"metadata":   map[string]any{"name": "foo"},
+				"status":     map[string]any{"conditions": []any{map[string]any{"type": "Stalled", "status": "True"}}},
+			},
+			want: ReadyStatus{Status: StatusFailed, Reason: "resource stalled"},
+		},
+		{
+			testName: "custom_resource_reconciling",
+			obj: map[string]any{
+				"apiVersion": "example.com/v1",
+				"kind":       "Foo",
+				"metadata":   map[string]any{"name": "foo"},
+				"status":     map[string]any{"conditions": []any{map[string]any{"type": "Reconciling", "status": "True", "message": "reconciling bar"}}},
+			},
+			want: ReadyStatus{Status: StatusInProgress, Reason: "reconciling bar"},
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got := ComputeReadyStatus(&unstructured.Unstructured{Object: d.obj})
+
+			if diff := cmp.Diff(d.want, got); diff != "" {
+				t.Errorf("ComputeReadyStatus() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
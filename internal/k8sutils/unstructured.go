@@ -1,19 +1,10 @@
 package k8sutils
 
-import (
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-)
-
-// UnstructuredListToObjects converts an unstructured list to a list of objects.
-func UnstructuredListToObjects(ul *unstructured.UnstructuredList) []any {
-	if ul == nil {
-		return []any{}
-	}
-
-	s := make([]any, len(ul.Items))
-	for i, v := range ul.Items {
-		s[i] = v.Object
-	}
-
-	return s
+// RemoveVolatileFields returns a copy of the object without the fields set by the API server which change independently
+// of the applied configuration; these are `status`, `metadata.creationTimestamp`, `metadata.generation`,
+// `metadata.resourceVersion`, `metadata.selfLink` and `metadata.managedFields[*].time`.
+func RemoveVolatileFields(obj map[string]any) map[string]any {
+	// The volatile field paths are static so sanitizing can't fail.
+	o, _ := SanitizeObject(obj, SanitizeOptions{})
+	return o
 }
@@ -4,46 +4,62 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
-func TestUnstructuredListToObjects(t *testing.T) {
+func TestRemoveVolatileFields(t *testing.T) {
 	t.Parallel()
 
 	for _, d := range []struct {
 		testName string
-		in       *unstructured.UnstructuredList
-		want     []any
+		in       map[string]any
+		want     map[string]any
 	}{
 		{
 			testName: "nil",
 			in:       nil,
-			want:     []any{},
-		},
-		{
-			testName: "empty",
-			in:       &unstructured.UnstructuredList{},
-			want:     []any{},
+			want:     nil,
 		},
 		{
-			testName: "single",
-			in:       &unstructured.UnstructuredList{Items: []unstructured.Unstructured{{Object: map[string]any{"foo": "bar"}}}},
-			want:     []any{map[string]any{"foo": "bar"}},
+			testName: "no_volatile_fields",
+			in:       map[string]any{"metadata": map[string]any{"name": "foo"}, "data": map[string]any{"foo": "bar"}},
+			want:     map[string]any{"metadata": map[string]any{"name": "foo"}, "data": map[string]any{"foo": "bar"}},
 		},
 		{
-			testName: "multiple",
-			in:       &unstructured.UnstructuredList{Items: []unstructured.Unstructured{{Object: map[string]any{"foo": "bar"}}, {Object: map[string]any{"foo": "baz"}}}},
-			want:     []any{map[string]any{"foo": "bar"}, map[string]any{"foo": "baz"}},
+			testName: "volatile_fields",
+			in: map[string]any{
+				"metadata": map[string]any{
+					"name":              "foo",
+					"uid":               "8e4b6a52-5c4f-4bf2-9d43-1c2c4a6f9f0e",
+					"creationTimestamp": "2025-01-01T00:00:00Z",
+					"generation":        int64(2),
+					"resourceVersion":   "1234",
+					"selfLink":          "/api/v1/namespaces/default/configmaps/foo",
+					"managedFields": []any{
+						map[string]any{"manager": "test", "operation": "Apply", "time": "2025-01-01T00:00:00Z"},
+					},
+				},
+				"spec":   map[string]any{"replicas": int64(1)},
+				"status": map[string]any{"replicas": int64(1)},
+			},
+			want: map[string]any{
+				"metadata": map[string]any{
+					"name": "foo",
+					"uid":  "8e4b6a52-5c4f-4bf2-9d43-1c2c4a6f9f0e",
+					"managedFields": []any{
+						map[string]any{"manager": "test", "operation": "Apply"},
+					},
+				},
+				"spec": map[string]any{"replicas": int64(1)},
+			},
 		},
 	} {
 		t.Run(d.testName, func(t *testing.T) {
 			t.Parallel()
 
-			got := UnstructuredListToObjects(d.in)
+			got := RemoveVolatileFields(d.in)
 
 			if diff := cmp.Diff(d.want, got); diff != "" {
-				t.Errorf("UnstructuredListToObjects() mismatch (-want +got):\n%s", diff)
+				t.Errorf("RemoveVolatileFields() mismatch (-want +got):\n%s", diff)
 			}
 		})
 	}
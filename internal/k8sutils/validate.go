@@ -0,0 +1,251 @@
+package k8sutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// ValidationError is an error found when validating an object against its OpenAPI schema.
+type ValidationError struct {
+	// Path is the path of the invalid field; the elements are field names (string) or list indexes (int).
+	Path []any
+	// Message describes the error.
+	Message string
+}
+
+// Error returns the error message prefixed with the field path.
+func (e ValidationError) Error() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s: %s", FormatFieldPath(e.Path), e.Message)
+}
+
+// FormatFieldPath formats a validation error path using the same syntax as GetFieldValue.
+func FormatFieldPath(p []any) string {
+	var sb strings.Builder
+	for _, e := range p {
+		switch v := e.(type) {
+		case int:
+			fmt.Fprintf(&sb, "[%d]", v)
+		case string:
+			if strings.ContainsAny(v, ".[]\"") {
+				fmt.Fprintf(&sb, "[%s]", strconv.Quote(v))
+				continue
+			}
+
+			if sb.Len() > 0 {
+				sb.WriteByte('.')
+			}
+
+			sb.WriteString(v)
+		}
+	}
+
+	return sb.String()
+}
+
+// ValidateObject validates an object against its OpenAPI schema and returns the errors found; the types, required
+// fields and enum values of the fields are checked, as are unknown fields of objects which don't allow additional
+// properties. Objects with properties are treated as not allowing additional properties unless they set
+// `additionalProperties` or `x-kubernetes-preserve-unknown-fields`, which matches how the API server handles built-in
+// types and structural schemas.
+func ValidateObject(obj map[string]any, s *OpenAPISchema) []ValidationError {
+	var errs []ValidationError
+	validateValue(obj, s.Schema, s, nil, &errs)
+	return errs
+}
+
+// validateValue validates a value against the schema, appending the errors found.
+func validateValue(v any, sc *spec.Schema, s *OpenAPISchema, p []any, errs *[]ValidationError) {
+	sc = s.Resolve(sc)
+	if sc == nil || v == nil {
+		return
+	}
+
+	if ok, _ := sc.Extensions.GetBool("x-kubernetes-int-or-string"); ok {
+		if _, isString := v.(string); !isString && !isInteger(v) {
+			appendValidationError(errs, p, "expected an integer or string, got %s", valueTypeName(v))
+		}
+
+		return
+	}
+
+	if ts := allowedTypes(sc, s); len(ts) > 0 && !slices.ContainsFunc(ts, func(t string) bool { return isType(v, t) }) {
+		appendValidationError(errs, p, "expected %s, got %s", strings.Join(ts, " or "), valueTypeName(v))
+		return
+	}
+
+	if len(sc.Enum) > 0 && !slices.ContainsFunc(sc.Enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(v) }) {
+		allowed := make([]string, 0, len(sc.Enum))
+		for _, e := range sc.Enum {
+			allowed = append(allowed, strconv.Quote(fmt.Sprint(e)))
+		}
+
+		appendValidationError(errs, p, "unsupported value %q, expected one of %s", fmt.Sprint(v), strings.Join(allowed, ", "))
+	}
+
+	switch vv := v.(type) {
+	case []any:
+		if sc.Items == nil || sc.Items.Schema == nil {
+			return
+		}
+
+		for i, e := range vv {
+			validateValue(e, sc.Items.Schema, s, appendPath(p, i), errs)
+		}
+	case map[string]any:
+		validateObject(vv, sc, s, p, errs)
+	}
+}
+
+// validateObject validates the fields of an object against the schema, appending the errors found.
+func validateObject(obj map[string]any, sc *spec.Schema, s *OpenAPISchema, p []any, errs *[]ValidationError) {
+	for _, k := range sc.Required {
+		if _, ok := obj[k]; !ok {
+			appendValidationError(errs, appendPath(p, k), "required field is missing")
+		}
+	}
+
+	preserveUnknownFields, _ := sc.Extensions.GetBool("x-kubernetes-preserve-unknown-fields")
+
+	var additional *spec.Schema
+	allowAdditional := preserveUnknownFields || len(sc.Properties) == 0
+	if ap := sc.AdditionalProperties; ap != nil {
+		additional = ap.Schema
+		allowAdditional = preserveUnknownFields || ap.Allows || ap.Schema != nil
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		if ps, ok := sc.Properties[k]; ok {
+			validateValue(obj[k], &ps, s, appendPath(p, k), errs)
+			continue
+		}
+
+		if !allowAdditional {
+			appendValidationError(errs, appendPath(p, k), "unknown field")
+			continue
+		}
+
+		if additional != nil {
+			validateValue(obj[k], additional, s, appendPath(p, k), errs)
+		}
+	}
+}
+
+// appendValidationError appends a validation error for the given path.
+func appendValidationError(errs *[]ValidationError, p []any, format string, a ...any) {
+	*errs = append(*errs, ValidationError{Path: p, Message: fmt.Sprintf(format, a...)})
+}
+
+// appendPath returns a copy of the path with the element appended.
+func appendPath(p []any, e any) []any {
+	return append(slices.Clone(p), e)
+}
+
+// allowedTypes returns the types allowed by the schema; types of schemas without a type, such as quantities, are
+// taken from their `oneOf` and `anyOf` schemas. If any type is allowed this is empty.
+func allowedTypes(sc *spec.Schema, s *OpenAPISchema) []string {
+	if len(sc.Type) > 0 {
+		return sc.Type
+	}
+
+	var ts []string
+	for _, o := range slices.Concat(sc.OneOf, sc.AnyOf) {
+		bs := s.Resolve(&o)
+		if bs == nil || len(bs.Type) == 0 {
+			return nil
+		}
+
+		ts = append(ts, bs.Type...)
+	}
+
+	return ts
+}
+
+// isType returns true if the value is of the given OpenAPI type.
+func isType(v any, t string) bool {
+	switch t {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "integer":
+		return isInteger(v)
+	case "number":
+		return isNumber(v)
+	default:
+		return true
+	}
+}
+
+// isInteger returns true if the value is an integer number.
+func isInteger(v any) bool {
+	switch n := v.(type) {
+	case int, int32, int64:
+		return true
+	case float64:
+		return n == math.Trunc(n) && !math.IsInf(n, 0)
+	case json.Number:
+		_, ok := new(big.Int).SetString(n.String(), 10)
+		return ok
+	default:
+		return false
+	}
+}
+
+// isNumber returns true if the value is a number.
+func isNumber(v any) bool {
+	switch v.(type) {
+	case int, int32, int64, float32, float64, json.Number:
+		return true
+	default:
+		return false
+	}
+}
+
+// valueTypeName returns the OpenAPI type name of the value.
+func valueTypeName(v any) string {
+	switch {
+	case isInteger(v):
+		return "integer"
+	case isNumber(v):
+		return "number"
+	}
+
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,195 @@
+package k8sutils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestValidateObject(t *testing.T) {
+	t.Parallel()
+
+	deployment := testOpenAPISchema(t, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+
+	closed := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:                 []string{"object"},
+		AdditionalProperties: &spec.SchemaOrBool{Allows: false},
+	}}
+
+	preserved := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:       []string{"object"},
+			Properties: map[string]spec.Schema{"foo": *spec.StringProperty()},
+		},
+		VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{"x-kubernetes-preserve-unknown-fields": true}},
+	}
+
+	newDeployment := func(s map[string]any) map[string]any {
+		return map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]any{"name": "foo", "labels": map[string]any{"app": "foo"}},
+			"spec":       s,
+		}
+	}
+
+	newSpec := func(container map[string]any) map[string]any {
+		return map[string]any{
+			"replicas": int64(1),
+			"selector": map[string]any{"matchLabels": map[string]any{"app": "foo"}},
+			"template": map[string]any{
+				"metadata": map[string]any{"labels": map[string]any{"app": "foo"}},
+				"spec":     map[string]any{"containers": []any{container}},
+			},
+		}
+	}
+
+	for _, d := range []struct {
+		testName string
+		obj      map[string]any
+		schema   *OpenAPISchema
+		want     []string
+	}{
+		{
+			testName: "valid",
+			obj: newDeployment(newSpec(map[string]any{
+				"name":      "foo",
+				"image":     "foo:latest",
+				"ports":     []any{map[string]any{"containerPort": json.Number("8080")}},
+				"resources": map[string]any{"limits": map[string]any{"cpu": int64(1), "memory": "128Mi"}},
+			})),
+			schema: deployment,
+		},
+		{
+			testName: "unknown_field",
+			obj: func() map[string]any {
+				s := newSpec(map[string]any{"name": "foo", "image": "foo:latest"})
+				s["replica"] = int64(1)
+				return newDeployment(s)
+			}(),
+			schema: deployment,
+			want:   []string{"spec.replica: unknown field"},
+		},
+		{
+			testName: "wrong_type",
+			obj: func() map[string]any {
+				s := newSpec(map[string]any{"name": "foo", "image": "foo:latest"})
+				s["replicas"] = "1"
+				return newDeployment(s)
+			}(),
+			schema: deployment,
+			want:   []string{"spec.replicas: expected integer, got string"},
+		},
+		{
+			testName: "wrong_int_or_string_type",
+			obj: newDeployment(newSpec(map[string]any{
+				"name":          "foo",
+				"image":         "foo:latest",
+				"livenessProbe": map[string]any{"httpGet": map[string]any{"port": 1.5}},
+			})),
+			schema: deployment,
+			want:   []string{"spec.template.spec.containers[0].livenessProbe.httpGet.port: expected integer or string, got number"},
+		},
+		{
+			testName: "missing_required_field",
+			obj:      newDeployment(newSpec(map[string]any{"image": "foo:latest"})),
+			schema:   deployment,
+			want:     []string{"spec.template.spec.containers[0].name: required field is missing"},
+		},
+		{
+			testName: "unsupported_enum_value",
+			obj:      map[string]any{"policy": "Sometimes"},
+			schema: &OpenAPISchema{Schema: &spec.Schema{SchemaProps: spec.SchemaProps{
+				Type:       []string{"object"},
+				Properties: map[string]spec.Schema{"policy": {SchemaProps: spec.SchemaProps{Type: []string{"string"}, Enum: []any{"Always", "IfNotPresent", "Never"}}}},
+			}}},
+			want: []string{`policy: unsupported value "Sometimes", expected one of "Always", "IfNotPresent", "Never"`},
+		},
+		{
+			testName: "quoted_field_name",
+			obj:      map[string]any{"foo": map[string]any{"app.kubernetes.io/name": "foo"}},
+			schema: &OpenAPISchema{Schema: &spec.Schema{SchemaProps: spec.SchemaProps{
+				Type:       []string{"object"},
+				Properties: map[string]spec.Schema{"foo": *closed},
+			}}},
+			want: []string{`foo["app.kubernetes.io/name"]: unknown field`},
+		},
+		{
+			testName: "preserve_unknown_fields",
+			obj:      map[string]any{"foo": int64(1), "bar": "bar"},
+			schema:   &OpenAPISchema{Schema: preserved},
+			want:     []string{"foo: expected string, got integer"},
+		},
+		{
+			testName: "multiple_errors",
+			obj: func() map[string]any {
+				s := newSpec(map[string]any{"name": int64(1), "image": "foo:latest"})
+				s["paused"] = "true"
+				s["replica"] = int64(1)
+				return newDeployment(s)
+			}(),
+			schema: deployment,
+			want: []string{
+				"spec.paused: expected boolean, got string",
+				"spec.replica: unknown field",
+				"spec.template.spec.containers[0].name: expected string, got integer",
+			},
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, err := range ValidateObject(d.obj, d.schema) {
+				got = append(got, err.Error())
+			}
+
+			if diff := cmp.Diff(d.want, got); diff != "" {
+				t.Errorf("ValidateObject() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFormatFieldPath(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []struct {
+		testName string
+		path     []any
+		want     string
+	}{
+		{
+			testName: "empty",
+			path:     nil,
+			want:     "",
+		},
+		{
+			testName: "fields",
+			path:     []any{"spec", "replicas"},
+			want:     "spec.replicas",
+		},
+		{
+			testName: "index",
+			path:     []any{"spec", "containers", 0, "name"},
+			want:     "spec.containers[0].name",
+		},
+		{
+			testName: "quoted",
+			path:     []any{"metadata", "annotations", "app.kubernetes.io/name"},
+			want:     `metadata.annotations["app.kubernetes.io/name"]`,
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := FormatFieldPath(d.path); got != d.want {
+				t.Errorf("FormatFieldPath() returned %q, want %q", got, d.want)
+			}
+		})
+	}
+}
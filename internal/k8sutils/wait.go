@@ -0,0 +1,181 @@
+package k8sutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// WaitCondition describes the state an object needs to reach.
+type WaitCondition struct {
+	// Rollout requires the object to be fully rolled out.
+	Rollout bool
+	// Conditions are the `status.conditions` the object needs to have.
+	Conditions []StatusCondition
+	// Fields maps field paths to the values they need to have.
+	Fields map[string]string
+}
+
+// StatusCondition describes a `status.conditions` item.
+type StatusCondition struct {
+	Type   string
+	Status string
+}
+
+// Check reports whether the object satisfies the wait condition; if it doesn't, the message describes the first unmet
+// requirement. An error is returned if the object can never satisfy the condition, such as for a failed job.
+func (w *WaitCondition) Check(obj *unstructured.Unstructured) (bool, string, error) {
+	if w.Rollout {
+		ok, msg, err := RolloutComplete(obj)
+		if err != nil || !ok {
+			return false, msg, err
+		}
+	}
+
+	for _, c := range w.Conditions {
+		status, ok := getConditionStatus(obj, c.Type)
+		if !ok {
+			return false, fmt.Sprintf("condition %q not found", c.Type), nil
+		}
+
+		if status != c.Status {
+			return false, fmt.Sprintf("condition %q has status %q, want %q", c.Type, status, c.Status), nil
+		}
+	}
+
+	for _, p := range slices.Sorted(maps.Keys(w.Fields)) {
+		want := w.Fields[p]
+		v, ok, err := GetFieldValue(obj.Object, p)
+		if err != nil {
+			return false, "", err
+		}
+
+		if !ok {
+			return false, fmt.Sprintf("field %q not found", p), nil
+		}
+
+		if got := fieldValueString(v); got != want {
+			return false, fmt.Sprintf("field %q has value %q, want %q", p, got, want), nil
+		}
+	}
+
+	return true, "", nil
+}
+
+// RolloutComplete reports whether the object has been fully rolled out; deployments, stateful sets, daemon sets and jobs
+// have their replica or completion status checked, other objects are rolled out once their `status.observedGeneration`
+// (if set) has caught up with `metadata.generation`. An error is returned for a failed job.
+func RolloutComplete(obj *unstructured.Unstructured) (bool, string, error) {
+	generation := obj.GetGeneration()
+	observed, ok, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if ok && observed < generation {
+		return false, fmt.Sprintf("observed generation %d is behind generation %d", observed, generation), nil
+	}
+
+	group := obj.GroupVersionKind().Group
+	switch {
+	case group == "apps" && obj.GetKind() == "Deployment":
+		if !ok {
+			return false, "observed generation not set", nil
+		}
+
+		replicas := getInt64(obj, 1, "spec", "replicas")
+		if updated := getInt64(obj, 0, "status", "updatedReplicas"); updated < replicas {
+			return false, fmt.Sprintf("%d of %d replicas updated", updated, replicas), nil
+		}
+
+		if total := getInt64(obj, 0, "status", "replicas"); total > replicas {
+			return false, fmt.Sprintf("%d old replicas pending termination", total-replicas), nil
+		}
+
+		if available := getInt64(obj, 0, "status", "availableReplicas"); available < replicas {
+			return false, fmt.Sprintf("%d of %d updated replicas available", available, replicas), nil
+		}
+	case group == "apps" && obj.GetKind() == "StatefulSet":
+		if !ok {
+			return false, "observed generation not set", nil
+		}
+
+		replicas := getInt64(obj, 1, "spec", "replicas")
+		if ready := getInt64(obj, 0, "status", "readyReplicas"); ready < replicas {
+			return false, fmt.Sprintf("%d of %d replicas ready", ready, replicas), nil
+		}
+
+		strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+		if strategy == "OnDelete" {
+			break
+		}
+
+		if updated := getInt64(obj, 0, "status", "updatedReplicas"); updated < replicas {
+			return false, fmt.Sprintf("%d of %d replicas updated", updated, replicas), nil
+		}
+
+		current, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+		update, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+		if current != update {
+			return false, fmt.Sprintf("current revision %q doesn't match update revision %q", current, update), nil
+		}
+	case group == "apps" && obj.GetKind() == "DaemonSet":
+		if !ok {
+			return false, "observed generation not set", nil
+		}
+
+		desired := getInt64(obj, 0, "status", "desiredNumberScheduled")
+		if updated := getInt64(obj, 0, "status", "updatedNumberScheduled"); updated < desired {
+			return false, fmt.Sprintf("%d of %d pods updated", updated, desired), nil
+		}
+
+		if available := getInt64(obj, 0, "status", "numberAvailable"); available < desired {
+			return false, fmt.Sprintf("%d of %d updated pods available", available, desired), nil
+		}
+	case group == "batch" && obj.GetKind() == "Job":
+		if status, ok := getConditionStatus(obj, "Failed"); ok && status == "True" {
+			return false, "job failed", fmt.Errorf("job %s failed", obj.GetName())
+		}
+
+		if status, ok := getConditionStatus(obj, "Complete"); !ok || status != "True" {
+			return false, "job not complete", nil
+		}
+	}
+
+	return true, "", nil
+}
+
+// fieldValueString returns the string representation of a field value used for comparisons; strings are returned as
+// is and all other values are JSON encoded.
+func fieldValueString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(b)
+}
+
+// getConditionStatus returns the status of the `status.conditions` item with the given type.
+func getConditionStatus(obj *unstructured.Unstructured, conditionType string) (string, bool) {
+	c, ok := getCondition(obj, conditionType)
+	if !ok {
+		return "", false
+	}
+
+	status, _ := c["status"].(string)
+	return status, true
+}
+
+// getInt64 returns the integer value of the object field, or the default if it isn't set.
+func getInt64(obj *unstructured.Unstructured, def int64, fields ...string) int64 {
+	v, ok, _ := unstructured.NestedInt64(obj.Object, fields...)
+	if !ok {
+		return def
+	}
+
+	return v
+}
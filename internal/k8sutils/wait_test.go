@@ -0,0 +1,272 @@
+package k8sutils
+
+import (
+	"regexp"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestWaitConditionCheck(t *testing.T) {
+	t.Parallel()
+
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]any{
+			"name": "foo",
+			"labels": map[string]any{
+				"app.kubernetes.io/name": "foo",
+			},
+		},
+		"status": map[string]any{
+			"phase": "Running",
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "True"},
+				map[string]any{"type": "PodScheduled", "status": "True"},
+			},
+			"containerStatuses": []any{
+				map[string]any{"name": "foo", "ready": true, "restartCount": int64(0)},
+			},
+		},
+	}}
+
+	for _, d := range []struct {
+		testName string
+		cond     *WaitCondition
+		want     bool
+		wantMsg  string
+		wantErr  *string
+	}{
+		{
+			testName: "empty",
+			cond:     &WaitCondition{},
+			want:     true,
+		},
+		{
+			testName: "rollout",
+			cond:     &WaitCondition{Rollout: true},
+			want:     true,
+		},
+		{
+			testName: "conditions_met",
+			cond:     &WaitCondition{Conditions: []StatusCondition{{Type: "Ready", Status: "True"}, {Type: "PodScheduled", Status: "True"}}},
+			want:     true,
+		},
+		{
+			testName: "condition_status_mismatch",
+			cond:     &WaitCondition{Conditions: []StatusCondition{{Type: "Ready", Status: "False"}}},
+			wantMsg:  `condition "Ready" has status "True", want "False"`,
+		},
+		{
+			testName: "condition_not_found",
+			cond:     &WaitCondition{Conditions: []StatusCondition{{Type: "Initialized", Status: "True"}}},
+			wantMsg:  `condition "Initialized" not found`,
+		},
+		{
+			testName: "fields_met",
+			cond: &WaitCondition{Fields: map[string]string{
+				"status.phase":                              "Running",
+				"status.containerStatuses[0].ready":         "true",
+				"status.containerStatuses[0].restartCount":  "0",
+				`metadata.labels["app.kubernetes.io/name"]`: "foo",
+			}},
+			want: true,
+		},
+		{
+			testName: "field_mismatch",
+			cond:     &WaitCondition{Fields: map[string]string{"status.phase": "Succeeded"}},
+			wantMsg:  `field "status.phase" has value "Running", want "Succeeded"`,
+		},
+		{
+			testName: "field_not_found",
+			cond:     &WaitCondition{Fields: map[string]string{"status.containerStatuses[1].ready": "true"}},
+			wantMsg:  `field "status.containerStatuses[1].ready" not found`,
+		},
+		{
+			testName: "invalid_field_path",
+			cond:     &WaitCondition{Fields: map[string]string{"status..phase": "Running"}},
+			wantErr:  new(`invalid field path "status..phase": unexpected dot`),
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got, msg, err := d.cond.Check(obj)
+			if err != nil {
+				if d.wantErr == nil {
+					t.Errorf("Check() returned unexpected error: %v", err)
+				}
+
+				if !regexp.MustCompile(regexp.QuoteMeta(*d.wantErr)).MatchString(err.Error()) {
+					t.Errorf("Check() returned error %q, want %q", err.Error(), *d.wantErr)
+				}
+
+				return
+			}
+
+			if d.wantErr != nil {
+				t.Errorf("Check() returned no error, want %q", *d.wantErr)
+			}
+
+			if got != d.want {
+				t.Errorf("Check() = %t, want %t", got, d.want)
+			}
+
+			if msg != d.wantMsg {
+				t.Errorf("Check() message = %q, want %q", msg, d.wantMsg)
+			}
+		})
+	}
+}
+
+func TestRolloutComplete(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []struct {
+		testName string
+		obj      map[string]any
+		want     bool
+		wantMsg  string
+		wantErr  *string
+	}{
+		{
+			testName: "config_map",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]any{"name": "foo"},
+			},
+			want: true,
+		},
+		{
+			testName: "observed_generation_behind",
+			obj: map[string]any{
+				"apiVersion": "example.com/v1",
+				"kind":       "Foo",
+				"metadata":   map[string]any{"name": "foo", "generation": int64(2)},
+				"status":     map[string]any{"observedGeneration": int64(1)},
+			},
+			wantMsg: "observed generation 1 is behind generation 2",
+		},
+		{
+			testName: "deployment_complete",
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "foo", "generation": int64(1)},
+				"spec":       map[string]any{"replicas": int64(2)},
+				"status":     map[string]any{"observedGeneration": int64(1), "replicas": int64(2), "updatedReplicas": int64(2), "availableReplicas": int64(2)},
+			},
+			want: true,
+		},
+		{
+			testName: "deployment_not_observed",
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "foo", "generation": int64(1)},
+			},
+			wantMsg: "observed generation not set",
+		},
+		{
+			testName: "deployment_updating",
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "foo", "generation": int64(1)},
+				"status":     map[string]any{"observedGeneration": int64(1), "replicas": int64(1)},
+			},
+			wantMsg: "0 of 1 replicas updated",
+		},
+		{
+			testName: "deployment_terminating",
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "foo", "generation": int64(1)},
+				"spec":       map[string]any{"replicas": int64(2)},
+				"status":     map[string]any{"observedGeneration": int64(1), "replicas": int64(3), "updatedReplicas": int64(2), "availableReplicas": int64(2)},
+			},
+			wantMsg: "1 old replicas pending termination",
+		},
+		{
+			testName: "stateful_set_revision_mismatch",
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "StatefulSet",
+				"metadata":   map[string]any{"name": "foo", "generation": int64(1)},
+				"status":     map[string]any{"observedGeneration": int64(1), "readyReplicas": int64(1), "updatedReplicas": int64(1), "currentRevision": "foo-1", "updateRevision": "foo-2"},
+			},
+			wantMsg: `current revision "foo-1" doesn't match update revision "foo-2"`,
+		},
+		{
+			testName: "daemon_set_unavailable",
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "DaemonSet",
+				"metadata":   map[string]any{"name": "foo", "generation": int64(1)},
+				"status":     map[string]any{"observedGeneration": int64(1), "desiredNumberScheduled": int64(3), "updatedNumberScheduled": int64(3), "numberAvailable": int64(2)},
+			},
+			wantMsg: "2 of 3 updated pods available",
+		},
+		{
+			testName: "job_complete",
+			obj: map[string]any{
+				"apiVersion": "batch/v1",
+				"kind":       "Job",
+				"metadata":   map[string]any{"name": "foo"},
+				"status":     map[string]any{"conditions": []any{map[string]any{"type": "Complete", "status": "True"}}},
+			},
+			want: true,
+		},
+		{
+			testName: "job_running",
+			obj: map[string]any{
+				"apiVersion": "batch/v1",
+				"kind":       "Job",
+				"metadata":   map[string]any{"name": "foo"},
+			},
+			wantMsg: "job not complete",
+		},
+		{
+			testName: "job_failed",
+			obj: map[string]any{
+				"apiVersion": "batch/v1",
+				"kind":       "Job",
+				"metadata":   map[string]any{"name": "foo"},
+				"status":     map[string]any{"conditions": []any{map[string]any{"type": "Failed", "status": "True"}}},
+			},
+			wantErr: new("job foo failed"),
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got, msg, err := RolloutComplete(&unstructured.Unstructured{Object: d.obj})
+			if err != nil {
+				if d.wantErr == nil {
+					t.Errorf("RolloutComplete() returned unexpected error: %v", err)
+				}
+
+				if !regexp.MustCompile(regexp.QuoteMeta(*d.wantErr)).MatchString(err.Error()) {
+					t.Errorf("RolloutComplete() returned error %q, want %q", err.Error(), *d.wantErr)
+				}
+
+				return
+			}
+
+			if d.wantErr != nil {
+				t.Errorf("RolloutComplete() returned no error, want %q", *d.wantErr)
+			}
+
+			if got != d.want {
+				t.Errorf("RolloutComplete() = %t, want %t", got, d.want)
+			}
+
+			if msg != d.wantMsg {
+				t.Errorf("RolloutComplete() message = %q, want %q", msg, d.wantMsg)
+			}
+		})
+	}
+}
@@ -2,30 +2,102 @@ package provider
 
 import (
 	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/terr4m/terraform-provider-k8s/internal/k8sutils"
+
+	"golang.org/x/sync/singleflight"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/disk"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/openapi3"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	aggregator "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
+	"k8s.io/kube-openapi/pkg/spec3"
+)
+
+var (
+	// cacheDirNameRegexp matches the characters which aren't allowed in a cache directory name.
+	cacheDirNameRegexp = regexp.MustCompile(`[^\w.-]`)
+	// discoveryCacheDirNameRegexp matches the characters which aren't allowed in a discovery cache directory name; this
+	// is the same as kubectl so the cache can be shared.
+	discoveryCacheDirNameRegexp = regexp.MustCompile(`[^(\w/.)]`)
 )
 
+// K8sProviderClientOption configures a K8s provider client.
+type K8sProviderClientOption func(*K8sProviderClient)
+
+// WithOpenAPICacheDir sets the directory OpenAPI v3 documents are cached in; if this is empty the documents are only
+// cached in memory.
+func WithOpenAPICacheDir(dir string) K8sProviderClientOption {
+	return func(c *K8sProviderClient) {
+		c.openAPICacheDir = dir
+	}
+}
+
+// WithDiscoveryCache sets the directory discovery information is cached in and how long it's valid for; if the directory
+// is empty the discovery information is only cached in memory.
+func WithDiscoveryCache(dir string, ttl time.Duration) K8sProviderClientOption {
+	return func(c *K8sProviderClient) {
+		c.discoveryCacheDir = dir
+		c.discoveryCacheTTL = ttl
+	}
+}
+
+// WithDefaultNamespace sets the namespace used for namespaced objects which don't set one.
+func WithDefaultNamespace(namespace string) K8sProviderClientOption {
+	return func(c *K8sProviderClient) {
+		c.defaultNamespace = namespace
+	}
+}
+
 // NewK8sProviderClient creates a new K8s provider client.
-func NewK8sProviderClient(restConfig *rest.Config) *K8sProviderClient {
-	return &K8sProviderClient{
+func NewK8sProviderClient(restConfig *rest.Config, opts ...K8sProviderClientOption) *K8sProviderClient {
+	c := &K8sProviderClient{
 		restConfig: restConfig,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
-// K8sProviderClient is a K8s provider client.
+// K8sProviderClient is a K8s provider client; the clients are created on first use and are safe to use concurrently, as
+// Terraform calls resources and data sources in parallel.
 type K8sProviderClient struct {
-	restConfig       *rest.Config
+	restConfig        *rest.Config
+	defaultNamespace  string
+	openAPICacheDir   string
+	discoveryCacheDir string
+	discoveryCacheTTL time.Duration
+
+	aggregatorMutex  sync.Mutex
 	aggregatorClient aggregator.Interface
-	discoveryClient  discovery.CachedDiscoveryInterface
-	dynamicClient    dynamic.Interface
-	restMapper       meta.ResettableRESTMapper
+
+	discoveryMutex  sync.Mutex
+	discoveryClient discovery.CachedDiscoveryInterface
+
+	dynamicMutex  sync.Mutex
+	dynamicClient dynamic.Interface
+
+	restMapperMutex sync.Mutex
+	restMapper      meta.ResettableRESTMapper
+
+	openAPIMutex      sync.Mutex
+	openAPIGroup      singleflight.Group
+	openAPIGeneration uint64
+	openAPIRoot       openapi3.Root
+	openAPIDocuments  map[schema.GroupVersion]*spec3.OpenAPI
 }
 
 // AggregatorClientset returns an aggregator K8s clientset.
@@ -34,6 +106,9 @@ func (c *K8sProviderClient) AggregatorClient() (aggregator.Interface, error) {
 		return nil, fmt.Errorf("rest config is required")
 	}
 
+	c.aggregatorMutex.Lock()
+	defer c.aggregatorMutex.Unlock()
+
 	if c.aggregatorClient != nil {
 		return c.aggregatorClient, nil
 	}
@@ -47,16 +122,33 @@ func (c *K8sProviderClient) AggregatorClient() (aggregator.Interface, error) {
 	return c.aggregatorClient, nil
 }
 
-// DiscoveryClient returns a discovery K8s client.
+// DiscoveryClient returns a discovery K8s client; the discovery information is cached on disk if a discovery cache
+// directory is configured, otherwise it's cached in memory.
 func (c *K8sProviderClient) DiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
 	if c.restConfig == nil {
 		return nil, fmt.Errorf("rest config is required")
 	}
 
+	c.discoveryMutex.Lock()
+	defer c.discoveryMutex.Unlock()
+
 	if c.discoveryClient != nil {
 		return c.discoveryClient, nil
 	}
 
+	if len(c.discoveryCacheDir) > 0 {
+		discoveryDir := filepath.Join(c.discoveryCacheDir, "discovery", discoveryCacheDirName(c.restConfig.Host))
+		httpDir := filepath.Join(c.discoveryCacheDir, "http")
+
+		dc, err := disk.NewCachedDiscoveryClientForConfig(c.restConfig, discoveryDir, httpDir, c.discoveryCacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure discovery client: %w", err)
+		}
+		c.discoveryClient = dc
+
+		return c.discoveryClient, nil
+	}
+
 	dc, err := discovery.NewDiscoveryClientForConfig(c.restConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to configure discovery client: %w", err)
@@ -73,6 +165,9 @@ func (c *K8sProviderClient) DynamicClient() (dynamic.Interface, error) {
 		return nil, fmt.Errorf("rest config is required")
 	}
 
+	c.dynamicMutex.Lock()
+	defer c.dynamicMutex.Unlock()
+
 	if c.dynamicClient != nil {
 		return c.dynamicClient, nil
 	}
@@ -86,12 +181,16 @@ func (c *K8sProviderClient) DynamicClient() (dynamic.Interface, error) {
 	return c.dynamicClient, nil
 }
 
-// RESTMapper returns a REST mapper.
+// RESTMapper returns a REST mapper; resetting it also clears the cached OpenAPI documents, as they can be out of date
+// for the same reasons as the discovery information.
 func (c *K8sProviderClient) RESTMapper() (meta.ResettableRESTMapper, error) {
 	if c.restConfig == nil {
 		return nil, fmt.Errorf("rest config is required")
 	}
 
+	c.restMapperMutex.Lock()
+	defer c.restMapperMutex.Unlock()
+
 	if c.restMapper != nil {
 		return c.restMapper, nil
 	}
@@ -101,7 +200,133 @@ func (c *K8sProviderClient) RESTMapper() (meta.ResettableRESTMapper, error) {
 		return nil, err
 	}
 
-	c.restMapper = restmapper.NewDeferredDiscoveryRESTMapper(dc)
+	c.restMapper = &openAPIResettingRESTMapper{
+		ResettableRESTMapper: restmapper.NewDeferredDiscoveryRESTMapper(dc),
+		client:               c,
+	}
 
 	return c.restMapper, nil
 }
+
+// OpenAPISchema returns the OpenAPI v3 schema for the given GroupVersionKind; the document for each group version is
+// fetched once and cached, and if an OpenAPI cache directory is configured it is also cached on disk keyed by the
+// server host and version.
+func (c *K8sProviderClient) OpenAPISchema(gvk schema.GroupVersionKind) (*k8sutils.OpenAPISchema, error) {
+	doc, err := c.openAPIDocument(gvk.GroupVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	return k8sutils.NewOpenAPISchema(doc, gvk)
+}
+
+// openAPIDocument returns the OpenAPI v3 document for the given group version; concurrent requests for the same group
+// version share a single fetch, which is done without holding the lock so other group versions aren't blocked.
+func (c *K8sProviderClient) openAPIDocument(gv schema.GroupVersion) (*spec3.OpenAPI, error) {
+	c.openAPIMutex.Lock()
+	doc, ok := c.openAPIDocuments[gv]
+	root := c.openAPIRoot
+	generation := c.openAPIGeneration
+	c.openAPIMutex.Unlock()
+
+	if ok {
+		return doc, nil
+	}
+
+	v, err, _ := c.openAPIGroup.Do(fmt.Sprintf("%d/%s", generation, gv.String()), func() (any, error) {
+		if root == nil {
+			var err error
+			if root, err = c.newOpenAPIRoot(); err != nil {
+				return nil, err
+			}
+		}
+
+		doc, err := root.GVSpec(gv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get openapi document for %s: %w", gv.String(), err)
+		}
+
+		c.openAPIMutex.Lock()
+		defer c.openAPIMutex.Unlock()
+
+		// Documents fetched before the cache was reset aren't stored, as they can be out of date.
+		if c.openAPIGeneration == generation {
+			if c.openAPIRoot == nil {
+				c.openAPIRoot = root
+			}
+
+			if c.openAPIDocuments == nil {
+				c.openAPIDocuments = map[schema.GroupVersion]*spec3.OpenAPI{}
+			}
+			c.openAPIDocuments[gv] = doc
+		}
+
+		return doc, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	doc, ok = v.(*spec3.OpenAPI)
+	if !ok {
+		return nil, fmt.Errorf("expected *spec3.OpenAPI, got %T", v)
+	}
+
+	return doc, nil
+}
+
+// resetOpenAPI clears the cached OpenAPI documents and root, so they're fetched again on next use.
+func (c *K8sProviderClient) resetOpenAPI() {
+	c.openAPIMutex.Lock()
+	defer c.openAPIMutex.Unlock()
+
+	c.openAPIGeneration++
+	c.openAPIRoot = nil
+	c.openAPIDocuments = nil
+}
+
+// newOpenAPIRoot creates an OpenAPI v3 root, cached on disk if an OpenAPI cache directory is configured.
+func (c *K8sProviderClient) newOpenAPIRoot() (openapi3.Root, error) {
+	dc, err := c.DiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+
+	oc := dc.OpenAPIV3()
+
+	if len(c.openAPICacheDir) > 0 {
+		v, err := dc.ServerVersion()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get server version: %w", err)
+		}
+
+		dir := filepath.Join(c.openAPICacheDir, cacheDirName(c.restConfig.Host), cacheDirName(v.GitVersion))
+		oc = k8sutils.NewDiskCachedOpenAPIClient(oc, dir)
+	}
+
+	return openapi3.NewRoot(oc), nil
+}
+
+// openAPIResettingRESTMapper is a REST mapper which clears the OpenAPI documents of the client when it's reset.
+type openAPIResettingRESTMapper struct {
+	meta.ResettableRESTMapper
+	client *K8sProviderClient
+}
+
+// Reset resets the REST mapper and the OpenAPI documents of the client.
+func (m *openAPIResettingRESTMapper) Reset() {
+	m.ResettableRESTMapper.Reset()
+	m.client.resetOpenAPI()
+}
+
+// cacheDirName returns a directory name for the value which is safe to use in a cache path.
+func cacheDirName(v string) string {
+	v = strings.TrimPrefix(strings.TrimPrefix(v, "https://"), "http://")
+	return cacheDirNameRegexp.ReplaceAllString(v, "_")
+}
+
+// discoveryCacheDirName returns the discovery cache directory name for the host, using the same format as kubectl.
+func discoveryCacheDirName(host string) string {
+	host = strings.Replace(strings.Replace(host, "https://", "", 1), "http://", "", 1)
+	return discoveryCacheDirNameRegexp.ReplaceAllString(host, "_")
+}
@@ -1,10 +1,18 @@
 package provider
 
 import (
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery/cached/disk"
+	"k8s.io/client-go/openapi/openapitest"
 	"k8s.io/client-go/rest"
 	aggregator "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
 )
@@ -88,10 +96,13 @@ func TestK8sProviderClient(t *testing.T) {
 	t.Run("DiscoveryClient", func(t *testing.T) {
 		t.Parallel()
 
+		cacheDir := t.TempDir()
+
 		for _, d := range []struct {
 			testName   string
 			mockSetup  func() K8sProviderClient
 			restConfig *rest.Config
+			wantDisk   bool
 			errMsg     string
 		}{
 			{
@@ -118,6 +129,19 @@ func TestK8sProviderClient(t *testing.T) {
 					}
 				},
 			},
+			{
+				testName: "new_disk_cached_discovery_client",
+				mockSetup: func() K8sProviderClient {
+					return K8sProviderClient{
+						restConfig: &rest.Config{
+							Host: "https://example.com",
+						},
+						discoveryCacheDir: cacheDir,
+						discoveryCacheTTL: time.Hour,
+					}
+				},
+				wantDisk: true,
+			},
 		} {
 			t.Run(d.testName, func(t *testing.T) {
 				t.Parallel()
@@ -138,6 +162,10 @@ func TestK8sProviderClient(t *testing.T) {
 				if errMsg != d.errMsg {
 					t.Errorf("K8sProviderClient.DiscoveryClient returned error message %q, want %q", errMsg, d.errMsg)
 				}
+
+				if _, ok := got.(*disk.CachedDiscoveryClient); ok != d.wantDisk {
+					t.Errorf("K8sProviderClient.DiscoveryClient returned %T, want disk cached client %t", got, d.wantDisk)
+				}
 			})
 		}
 	})
@@ -256,4 +284,248 @@ func TestK8sProviderClient(t *testing.T) {
 			})
 		}
 	})
+	t.Run("OpenAPISchema", func(t *testing.T) {
+		t.Parallel()
+
+		for _, d := range []struct {
+			testName     string
+			mockSetup    func(t *testing.T) K8sProviderClient
+			gvk          schema.GroupVersionKind
+			wantCacheDir string
+			errMsg       string
+		}{
+			{
+				testName:  "rest_config_nil",
+				mockSetup: func(*testing.T) K8sProviderClient { return K8sProviderClient{} },
+				gvk:       schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+				errMsg:    "rest config is required",
+			},
+			{
+				testName: "memory_cache",
+				mockSetup: func(*testing.T) K8sProviderClient {
+					return K8sProviderClient{
+						restConfig:      &rest.Config{},
+						discoveryClient: &discoveryClientStub{openAPIClient: openapitest.NewEmbeddedFileClient()},
+					}
+				},
+				gvk: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			},
+			{
+				testName: "disk_cache",
+				mockSetup: func(t *testing.T) K8sProviderClient {
+					return K8sProviderClient{
+						restConfig:      &rest.Config{Host: "https://example.com:6443"},
+						openAPICacheDir: t.TempDir(),
+						discoveryClient: &discoveryClientStub{
+							openAPIClient: openapitest.NewEmbeddedFileClient(),
+							serverVersion: &version.Info{GitVersion: "v1.36.0+k3s1"},
+						},
+					}
+				},
+				gvk:          schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+				wantCacheDir: filepath.Join("example.com_6443", "v1.36.0_k3s1"),
+			},
+			{
+				testName: "unknown_kind",
+				mockSetup: func(*testing.T) K8sProviderClient {
+					return K8sProviderClient{
+						restConfig:      &rest.Config{},
+						discoveryClient: &discoveryClientStub{openAPIClient: openapitest.NewEmbeddedFileClient()},
+					}
+				},
+				gvk:    schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Foo"},
+				errMsg: "openapi schema not found for apps/v1, Kind=Foo",
+			},
+		} {
+			t.Run(d.testName, func(t *testing.T) {
+				t.Parallel()
+
+				client := d.mockSetup(t)
+
+				got, err := client.OpenAPISchema(d.gvk)
+
+				if len(d.errMsg) == 0 && got == nil {
+					t.Errorf("K8sProviderClient.OpenAPISchema returned nil, want non-nil")
+				}
+
+				var errMsg string
+				if err != nil {
+					errMsg = err.Error()
+				}
+
+				if errMsg != d.errMsg {
+					t.Errorf("K8sProviderClient.OpenAPISchema returned error message %q, want %q", errMsg, d.errMsg)
+				}
+
+				if err != nil {
+					return
+				}
+
+				if _, ok := client.openAPIDocuments[d.gvk.GroupVersion()]; !ok {
+					t.Errorf("K8sProviderClient.OpenAPISchema didn't cache the document for %s", d.gvk.GroupVersion())
+				}
+
+				if len(d.wantCacheDir) > 0 {
+					entries, err := os.ReadDir(filepath.Join(client.openAPICacheDir, d.wantCacheDir))
+					if err != nil {
+						t.Fatalf("failed to read the cache directory: %v", err)
+					}
+
+					if len(entries) != 1 {
+						t.Errorf("cache directory contains %d entries, want 1", len(entries))
+					}
+				}
+			})
+		}
+	})
+	t.Run("RESTMapperReset", func(t *testing.T) {
+		t.Parallel()
+
+		client := &K8sProviderClient{
+			restConfig:      &rest.Config{},
+			discoveryClient: &discoveryClientStub{openAPIClient: openapitest.NewEmbeddedFileClient()},
+		}
+
+		gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+		if _, err := client.OpenAPISchema(gvk); err != nil {
+			t.Fatalf("K8sProviderClient.OpenAPISchema returned unexpected error: %v", err)
+		}
+
+		stub := &restMapperStub{}
+		rm := &openAPIResettingRESTMapper{ResettableRESTMapper: stub, client: client}
+		rm.Reset()
+
+		if stub.resets != 1 {
+			t.Errorf("openAPIResettingRESTMapper.Reset reset the REST mapper %d times, want 1", stub.resets)
+		}
+
+		if len(client.openAPIDocuments) > 0 || client.openAPIRoot != nil {
+			t.Errorf("openAPIResettingRESTMapper.Reset didn't clear the OpenAPI cache")
+		}
+
+		if _, err := client.OpenAPISchema(gvk); err != nil {
+			t.Fatalf("K8sProviderClient.OpenAPISchema returned unexpected error: %v", err)
+		}
+
+		if _, ok := client.openAPIDocuments[gvk.GroupVersion()]; !ok {
+			t.Errorf("K8sProviderClient.OpenAPISchema didn't cache the document for %s after the reset", gvk.GroupVersion())
+		}
+	})
+	t.Run("ConcurrentOpenAPIDocument", func(t *testing.T) {
+		t.Parallel()
+
+		oc := &openAPIClientStub{Client: openapitest.NewEmbeddedFileClient()}
+		client := &K8sProviderClient{
+			restConfig:      &rest.Config{},
+			discoveryClient: &discoveryClientStub{openAPIClient: oc},
+		}
+
+		const n = 16
+
+		var wg sync.WaitGroup
+		errs := make([]error, n)
+		for i := range n {
+			wg.Go(func() {
+				_, errs[i] = client.openAPIDocument(schema.GroupVersion{Group: "apps", Version: "v1"})
+			})
+		}
+		wg.Wait()
+
+		for i := range n {
+			if errs[i] != nil {
+				t.Fatalf("K8sProviderClient.openAPIDocument returned unexpected error: %v", errs[i])
+			}
+		}
+
+		if got := oc.fetches.Load(); got != 1 {
+			t.Errorf("K8sProviderClient.openAPIDocument fetched the document %d times, want 1", got)
+		}
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		t.Parallel()
+
+		for _, d := range []struct {
+			testName string
+			get      func(c *K8sProviderClient) (any, error)
+		}{
+			{
+				testName: "AggregatorClient",
+				get:      func(c *K8sProviderClient) (any, error) { return c.AggregatorClient() },
+			},
+			{
+				testName: "DiscoveryClient",
+				get:      func(c *K8sProviderClient) (any, error) { return c.DiscoveryClient() },
+			},
+			{
+				testName: "DynamicClient",
+				get:      func(c *K8sProviderClient) (any, error) { return c.DynamicClient() },
+			},
+			{
+				testName: "RESTMapper",
+				get:      func(c *K8sProviderClient) (any, error) { return c.RESTMapper() },
+			},
+		} {
+			t.Run(d.testName, func(t *testing.T) {
+				t.Parallel()
+
+				client := NewK8sProviderClient(&rest.Config{Host: "https://example.com"})
+
+				const n = 16
+
+				var wg sync.WaitGroup
+				got := make([]any, n)
+				errs := make([]error, n)
+				for i := range n {
+					wg.Go(func() {
+						got[i], errs[i] = d.get(client)
+					})
+				}
+				wg.Wait()
+
+				for i := range n {
+					if errs[i] != nil {
+						t.Fatalf("K8sProviderClient.%s returned unexpected error: %v", d.testName, errs[i])
+					}
+
+					if got[i] != got[0] {
+						t.Errorf("K8sProviderClient.%s returned a different client on call %d", d.testName, i)
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestDiscoveryCacheDirName(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []struct {
+		testName string
+		host     string
+		want     string
+	}{
+		{
+			testName: "https",
+			host:     "https://example.com:6443",
+			want:     "example.com_6443",
+		},
+		{
+			testName: "http",
+			host:     "http://my-cluster.example.com",
+			want:     "my_cluster.example.com",
+		},
+		{
+			testName: "path",
+			host:     "https://example.com/k8s/clusters/c-1",
+			want:     "example.com/k8s/clusters/c_1",
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := discoveryCacheDirName(d.host); got != d.want {
+				t.Errorf("discoveryCacheDirName() returned %q, want %q", got, d.want)
+			}
+		})
+	}
 }
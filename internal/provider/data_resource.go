@@ -10,9 +10,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 var (
@@ -32,12 +35,60 @@ type ResourceDataSource struct {
 
 // ResourceDataSourceModel describes the data source data model.
 type ResourceDataSourceModel struct {
-	APIVersion types.String   `tfsdk:"api_version"`
-	Kind       types.String   `tfsdk:"kind"`
-	Namespace  types.String   `tfsdk:"namespace"`
-	Name       types.String   `tfsdk:"name"`
-	Object     types.Dynamic  `tfsdk:"object"`
-	Timeouts   timeouts.Value `tfsdk:"timeouts"`
+	APIVersion    types.String      `tfsdk:"api_version"`
+	Kind          types.String      `tfsdk:"kind"`
+	Namespace     types.String      `tfsdk:"namespace"`
+	Name          types.String      `tfsdk:"name"`
+	IncludeStatus types.Bool        `tfsdk:"include_status"`
+	ExcludePaths  types.List        `tfsdk:"exclude_paths"`
+	TypedObject   types.Bool        `tfsdk:"typed_object"`
+	Object        types.Dynamic     `tfsdk:"object"`
+	ReadyStatus   *ReadyStatusModel `tfsdk:"ready_status"`
+	Timeouts      timeouts.Value    `tfsdk:"timeouts"`
+}
+
+// ReadyStatusModel describes the readiness status of a resource.
+type ReadyStatusModel struct {
+	Status types.String `tfsdk:"status"`
+	Reason types.String `tfsdk:"reason"`
+}
+
+// newReadyStatusModel creates a ready status model for the given object.
+func newReadyStatusModel(obj *unstructured.Unstructured) ReadyStatusModel {
+	rs := k8sutils.ComputeReadyStatus(obj)
+
+	return ReadyStatusModel{
+		Status: types.StringValue(string(rs.Status)),
+		Reason: types.StringValue(rs.Reason),
+	}
+}
+
+// readyStatusAttributes returns the schema attributes of a ready status.
+func readyStatusAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"status": schema.StringAttribute{
+			MarkdownDescription: "Readiness status of the resource; this is one of `InProgress`, `Current`, `Failed` or `Terminating`.",
+			Computed:            true,
+		},
+		"reason": schema.StringAttribute{
+			MarkdownDescription: "Reason for the readiness status.",
+			Computed:            true,
+		},
+	}
+}
+
+// sanitizeOptions returns the options for sanitizing the objects returned by a data source.
+func sanitizeOptions(ctx context.Context, includeStatus types.Bool, excludePaths types.List) (k8sutils.SanitizeOptions, diag.Diagnostics) {
+	opts := k8sutils.SanitizeOptions{
+		IncludeStatus: includeStatus.ValueBool(),
+	}
+
+	var diagnostics diag.Diagnostics
+	if !excludePaths.IsNull() && !excludePaths.IsUnknown() {
+		diagnostics.Append(excludePaths.ElementsAs(ctx, &opts.ExcludePaths, false)...)
+	}
+
+	return opts, diagnostics
 }
 
 // Metadata returns the data source metadata.
@@ -66,10 +117,28 @@ func (d *ResourceDataSource) Schema(ctx context.Context, _ datasource.SchemaRequ
 				MarkdownDescription: "Name of the resource to find.",
 				Required:            true,
 			},
+			"include_status": schema.BoolAttribute{
+				MarkdownDescription: "If `true`, the `status` field is returned as part of the object; this defaults to `false`.",
+				Optional:            true,
+			},
+			"exclude_paths": schema.ListAttribute{
+				MarkdownDescription: "Additional field paths to remove from the returned object; paths are made up of field names separated by dots, list indexes in brackets (e.g. `[0]`), `[*]` for all list items and quoted field names in brackets for names containing dots (e.g. `metadata.annotations[\"app.kubernetes.io/name\"]`); a trailing `[*]` removes the whole list.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"typed_object": schema.BoolAttribute{
+				MarkdownDescription: "If `true`, `object` is decoded using the _OpenAPI_ schema of the resource kind so lists and maps have a single element type, with missing object fields set to `null`; this defaults to `false`. Fields without a schema, such as those preserving unknown fields, are decoded in the same way as when this is `false`.",
+				Optional:            true,
+			},
 			"object": schema.DynamicAttribute{
-				MarkdownDescription: "Resource object retrieved from the API server. The following fields are not returned; `status`, `metadata.creationTimestamp`, `metadata.generation`, `metadata.resourceVersion`, `metadata.selfLink`, `metadata.managedFields[*].time`.",
+				MarkdownDescription: "Resource object retrieved from the API server; empty maps and lists, such as `emptyDir: {}`, are kept. The following fields are not returned; `status` (unless `include_status` is set), `metadata.creationTimestamp`, `metadata.generation`, `metadata.resourceVersion`, `metadata.selfLink`, `metadata.managedFields[*].time`.",
 				Computed:            true,
 			},
+			"ready_status": schema.SingleNestedAttribute{
+				MarkdownDescription: "Readiness status of the resource computed from its status; deployments, stateful sets, daemon sets, jobs, pods, persistent volume claims and load balancer services have their kind specific status checked, all other resources are evaluated using their `status.observedGeneration` and their `Ready`, `Reconciling` and `Stalled` conditions.",
+				Computed:            true,
+				Attributes:          readyStatusAttributes(),
+			},
 			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
 				Read:            true,
 				ReadDescription: "Timeout for reading the data source; this defaults to the provider value if not set. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
@@ -145,11 +214,37 @@ func (d *ResourceDataSource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
-	obj, diags := tfutils.DecodeDynamic(ctx, o.Object)
+	sanitizeOpts, diags := sanitizeOptions(ctx, data.IncludeStatus, data.ExcludePaths)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	so, err := k8sutils.SanitizeObject(o.Object, sanitizeOpts)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("exclude_paths"), "Invalid exclude path.", err.Error())
+		return
+	}
+
+	var obj types.Dynamic
+	if data.TypedObject.ValueBool() {
+		s, err := d.providerData.Client.OpenAPISchema(*gvk)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to get OpenAPI schema.", err.Error())
+			return
+		}
+
+		obj, diags = tfutils.DecodeDynamicWithSchema(ctx, so, s.Schema, s.Resolve, tfutils.WithEmptyCollections())
+	} else {
+		obj, diags = tfutils.DecodeDynamic(ctx, so, tfutils.WithEmptyCollections())
+	}
+
 	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
 		return
 	}
 	data.Object = obj
 
+	rs := newReadyStatusModel(o)
+	data.ReadyStatus = &rs
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -27,6 +27,7 @@ func TestAccResourceDataSource(t *testing.T) {
 					ConfigStateChecks: []statecheck.StateCheck{
 						statecheck.ExpectKnownValue("data.k8s_resource.test", tfjsonpath.New("object"), knownvalue.NotNull()),
 						statecheck.ExpectKnownValue("data.k8s_resource.test", tfjsonpath.New("object").AtMapKey("metadata").AtMapKey("name"), knownvalue.StringExact(name)),
+						statecheck.ExpectKnownValue("data.k8s_resource.test", tfjsonpath.New("ready_status").AtMapKey("status"), knownvalue.StringExact("Current")),
 					},
 				},
 			},
@@ -52,9 +53,136 @@ func TestAccResourceDataSource(t *testing.T) {
 						statecheck.ExpectKnownValue("data.k8s_resource.test", tfjsonpath.New("object"), knownvalue.NotNull()),
 						statecheck.ExpectKnownValue("data.k8s_resource.test", tfjsonpath.New("object").AtMapKey("metadata").AtMapKey("namespace"), knownvalue.StringExact(namespace)),
 						statecheck.ExpectKnownValue("data.k8s_resource.test", tfjsonpath.New("object").AtMapKey("metadata").AtMapKey("name"), knownvalue.StringExact(name)),
+						statecheck.ExpectKnownValue("data.k8s_resource.test", tfjsonpath.New("ready_status").AtMapKey("status"), knownvalue.StringExact("Current")),
 					},
 				},
 			},
 		})
 	})
+	t.Run("include_status", func(t *testing.T) {
+		name := "default"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`data "k8s_resource" "test" {
+  api_version    = "v1"
+  kind           = "Namespace"
+  name           = "%s"
+  include_status = true
+  exclude_paths  = ["metadata.managedFields", "metadata.uid"]
+}`, name),
+					ConfigStateChecks: []statecheck.StateCheck{
+						statecheck.ExpectKnownValue("data.k8s_resource.test", tfjsonpath.New("object").AtMapKey("metadata").AtMapKey("name"), knownvalue.StringExact(name)),
+						statecheck.ExpectKnownValue("data.k8s_resource.test", tfjsonpath.New("object").AtMapKey("status").AtMapKey("phase"), knownvalue.StringExact("Active")),
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("typed_object", func(t *testing.T) {
+		name := "cluster-admin"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`data "k8s_resource" "test" {
+  api_version  = "rbac.authorization.k8s.io/v1"
+  kind         = "ClusterRole"
+  name         = "%s"
+  typed_object = true
+}`, name),
+					ConfigStateChecks: []statecheck.StateCheck{
+						statecheck.ExpectKnownValue("data.k8s_resource.test", tfjsonpath.New("object").AtMapKey("metadata").AtMapKey("name"), knownvalue.StringExact(name)),
+						statecheck.ExpectKnownValue("data.k8s_resource.test", tfjsonpath.New("object").AtMapKey("rules").AtSliceIndex(0).AtMapKey("verbs"), knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("*")})),
+					},
+				},
+			},
+		})
+	})
+	t.Run("empty_collections", func(t *testing.T) {
+		namespace := "default"
+		name := "tf-acc-data-resource-empty"
+
+		for _, typed := range []bool{false, true} {
+			t.Run(fmt.Sprintf("typed_object_%t", typed), func(t *testing.T) {
+				var emptyDir knownvalue.Check = knownvalue.MapExact(map[string]knownvalue.Check{})
+				if typed {
+					emptyDir = knownvalue.NotNull()
+				}
+
+				resource.Test(t, resource.TestCase{
+					PreCheck:                 func() { testAccPreCheck(t) },
+					ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+					Steps: []resource.TestStep{
+						{
+							Config: fmt.Sprintf(`resource "k8s_resource" "test" {
+  manifest = {
+    apiVersion = "apps/v1"
+    kind       = "Deployment"
+    metadata = {
+      namespace = "%[1]s"
+      name      = "%[2]s"
+    }
+    spec = {
+      replicas = 0
+      selector = {
+        matchLabels = {
+          app = "%[2]s"
+        }
+      }
+      template = {
+        metadata = {
+          labels = {
+            app = "%[2]s"
+          }
+        }
+        spec = {
+          containers = [
+            {
+              name  = "test"
+              image = "busybox"
+              volumeMounts = [
+                {
+                  name      = "cache"
+                  mountPath = "/cache"
+                }
+              ]
+            }
+          ]
+          volumes = [
+            {
+              name     = "cache"
+              emptyDir = {}
+            }
+          ]
+        }
+      }
+    }
+  }
+}
+
+data "k8s_resource" "test" {
+  api_version  = "apps/v1"
+  kind         = "Deployment"
+  namespace    = "%[1]s"
+  name         = "%[2]s"
+  typed_object = %[3]t
+
+  depends_on = [k8s_resource.test]
+}`, namespace, name, typed),
+							ConfigStateChecks: []statecheck.StateCheck{
+								statecheck.ExpectKnownValue("data.k8s_resource.test", tfjsonpath.New("object").AtMapKey("spec").AtMapKey("template").AtMapKey("spec").AtMapKey("volumes").AtSliceIndex(0).AtMapKey("emptyDir"), emptyDir),
+							},
+						},
+					},
+				})
+			})
+		}
+	})
 }
@@ -11,9 +11,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
 )
 
 var (
@@ -33,14 +37,18 @@ type ResourcesDataSource struct {
 
 // ResourcesDataSourceModel describes the data source data model.
 type ResourcesDataSourceModel struct {
-	APIVersion    types.String   `tfsdk:"api_version"`
-	Kind          types.String   `tfsdk:"kind"`
-	Namespace     types.String   `tfsdk:"namespace"`
-	FieldSelector types.String   `tfsdk:"field_selector"`
-	LabelSelector types.String   `tfsdk:"label_selector"`
-	Limit         types.Number   `tfsdk:"limit"`
-	Objects       types.Dynamic  `tfsdk:"objects"`
-	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+	APIVersion    types.String       `tfsdk:"api_version"`
+	Kind          types.String       `tfsdk:"kind"`
+	Namespace     types.String       `tfsdk:"namespace"`
+	FieldSelector types.String       `tfsdk:"field_selector"`
+	LabelSelector types.String       `tfsdk:"label_selector"`
+	Limit         types.Number       `tfsdk:"limit"`
+	IncludeStatus types.Bool         `tfsdk:"include_status"`
+	ExcludePaths  types.List         `tfsdk:"exclude_paths"`
+	TypedObject   types.Bool         `tfsdk:"typed_object"`
+	Objects       types.Dynamic      `tfsdk:"objects"`
+	ReadyStatus   []ReadyStatusModel `tfsdk:"ready_status"`
+	Timeouts      timeouts.Value     `tfsdk:"timeouts"`
 }
 
 // Metadata returns the data source metadata.
@@ -77,10 +85,30 @@ func (d *ResourcesDataSource) Schema(ctx context.Context, _ datasource.SchemaReq
 				MarkdownDescription: "Limit the number of resources to find.",
 				Optional:            true,
 			},
+			"include_status": schema.BoolAttribute{
+				MarkdownDescription: "If `true`, the `status` field is returned as part of each object; this defaults to `false`.",
+				Optional:            true,
+			},
+			"exclude_paths": schema.ListAttribute{
+				MarkdownDescription: "Additional field paths to remove from each returned object; see the `k8s_resource` data source for the path syntax.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"typed_object": schema.BoolAttribute{
+				MarkdownDescription: "If `true`, each object in `objects` is decoded using the _OpenAPI_ schema of the resource kind; this defaults to `false`. See the `k8s_resource` data source for how objects are decoded.",
+				Optional:            true,
+			},
 			"objects": schema.DynamicAttribute{
-				MarkdownDescription: "List of resource objects retrieved from the API server. The following object fields are not returned; `status`, `metadata.creationTimestamp`, `metadata.generation`, `metadata.resourceVersion`, `metadata.selfLink`, `metadata.managedFields[*].time`.",
+				MarkdownDescription: "List of resource objects retrieved from the API server; empty maps and lists, such as `emptyDir: {}`, are kept. The following object fields are not returned; `status` (unless `include_status` is set), `metadata.creationTimestamp`, `metadata.generation`, `metadata.resourceVersion`, `metadata.selfLink`, `metadata.managedFields[*].time`.",
 				Computed:            true,
 			},
+			"ready_status": schema.ListNestedAttribute{
+				MarkdownDescription: "Readiness status of each resource in `objects`, in the same order; see the `k8s_resource` data source for how the status is computed.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: readyStatusAttributes(),
+				},
+			},
 			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
 				Read:            true,
 				ReadDescription: "Timeout for reading the data source; this defaults to the provider value if not set. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
@@ -112,36 +140,6 @@ func (d *ResourcesDataSource) Read(ctx context.Context, req datasource.ReadReque
 		return
 	}
 
-	gvk, err := k8sutils.ParseGVK(data.APIVersion.ValueString(), data.Kind.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to parse GVK.", err.Error())
-		return
-	}
-
-	rm, err := d.providerData.Client.RESTMapper()
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to configure REST mapper.", err.Error())
-		return
-	}
-
-	m, err := k8sutils.GetMapping(rm, gvk)
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to get REST mapping.", err.Error())
-		return
-	}
-
-	dc, err := d.providerData.Client.DynamicClient()
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to configure dynamic client.", err.Error())
-		return
-	}
-
-	ri, err := k8sutils.GetResourceInterface(dc, m, false, data.Namespace.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to configure resource interface.", err.Error())
-		return
-	}
-
 	limit := int64(0)
 	if !data.Limit.IsNull() {
 		limit, _ = data.Limit.ValueBigFloat().Int64()
@@ -161,22 +159,138 @@ func (d *ResourcesDataSource) Read(ctx context.Context, req datasource.ReadReque
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	l, err := ri.List(ctx, opts)
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to list resources.", err.Error())
+	l, diags := listResources(ctx, d.providerData.Client, data.APIVersion.ValueString(), data.Kind.ValueString(), data.Namespace.ValueString(), opts)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
 		return
 	}
 
-	col, diags := tfutils.DecodeDynamic(ctx, k8sutils.UnstructuredListToObjects(l))
+	sanitizeOpts, diags := sanitizeOptions(ctx, data.IncludeStatus, data.ExcludePaths)
 	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
 		return
 	}
 
-	if col.IsNull() {
-		col = types.DynamicValue(types.TupleValueMust([]attr.Type{}, []attr.Value{}))
+	objs := make([]any, 0, len(l.Items))
+	for _, o := range l.Items {
+		so, err := k8sutils.SanitizeObject(o.Object, sanitizeOpts)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("exclude_paths"), "Invalid exclude path.", err.Error())
+			return
+		}
+
+		objs = append(objs, so)
+	}
+
+	var col types.Dynamic
+	if data.TypedObject.ValueBool() {
+		col, diags = decodeTypedObjects(ctx, d.providerData.Client, data.APIVersion.ValueString(), data.Kind.ValueString(), objs)
+	} else {
+		col, diags = tfutils.DecodeDynamic(ctx, objs, tfutils.WithEmptyCollections())
+	}
+
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
 	}
 
 	data.Objects = col
 
+	data.ReadyStatus = make([]ReadyStatusModel, 0, len(l.Items))
+	for i := range l.Items {
+		data.ReadyStatus = append(data.ReadyStatus, newReadyStatusModel(&l.Items[i]))
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// decodeTypedObjects decodes the objects of the given API version and kind into a tuple, using the OpenAPI schema of
+// the kind for each object.
+func decodeTypedObjects(ctx context.Context, client *K8sProviderClient, apiVersion, kind string, objs []any) (types.Dynamic, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	gvk, err := k8sutils.ParseGVK(apiVersion, kind)
+	if err != nil {
+		diagnostics.AddError("Failed to parse GVK.", err.Error())
+		return types.Dynamic{}, diagnostics
+	}
+
+	s, err := client.OpenAPISchema(*gvk)
+	if err != nil {
+		diagnostics.AddError("Failed to get OpenAPI schema.", err.Error())
+		return types.Dynamic{}, diagnostics
+	}
+
+	tl := make([]attr.Type, 0, len(objs))
+	vl := make([]attr.Value, 0, len(objs))
+	for _, o := range objs {
+		obj, diags := tfutils.DecodeDynamicWithSchema(ctx, o, s.Schema, s.Resolve, tfutils.WithEmptyCollections())
+		if diagnostics.Append(diags...); diagnostics.HasError() {
+			return types.Dynamic{}, diagnostics
+		}
+
+		v := obj.UnderlyingValue()
+		tl = append(tl, v.Type(ctx))
+		vl = append(vl, v)
+	}
+
+	col, diags := types.TupleValue(tl, vl)
+	if diagnostics.Append(diags...); diagnostics.HasError() {
+		return types.Dynamic{}, diagnostics
+	}
+
+	return types.DynamicValue(col), diagnostics
+}
+
+// listResources lists the resources of the given API version and kind, optionally in the given namespace.
+func listResources(ctx context.Context, client *K8sProviderClient, apiVersion, kind, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	ri, diags := listResourceInterface(client, apiVersion, kind, namespace)
+	if diagnostics.Append(diags...); diagnostics.HasError() {
+		return nil, diagnostics
+	}
+
+	l, err := ri.List(ctx, opts)
+	if err != nil {
+		diagnostics.AddError("Failed to list resources.", err.Error())
+		return nil, diagnostics
+	}
+
+	return l, diagnostics
+}
+
+// listResourceInterface returns a dynamic resource interface for listing the resources of the given API version and
+// kind, optionally in the given namespace.
+func listResourceInterface(client *K8sProviderClient, apiVersion, kind, namespace string) (dynamic.ResourceInterface, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	gvk, err := k8sutils.ParseGVK(apiVersion, kind)
+	if err != nil {
+		diagnostics.AddError("Failed to parse GVK.", err.Error())
+		return nil, diagnostics
+	}
+
+	rm, err := client.RESTMapper()
+	if err != nil {
+		diagnostics.AddError("Failed to configure REST mapper.", err.Error())
+		return nil, diagnostics
+	}
+
+	m, err := k8sutils.GetMapping(rm, gvk)
+	if err != nil {
+		diagnostics.AddError("Failed to get REST mapping.", err.Error())
+		return nil, diagnostics
+	}
+
+	dc, err := client.DynamicClient()
+	if err != nil {
+		diagnostics.AddError("Failed to configure dynamic client.", err.Error())
+		return nil, diagnostics
+	}
+
+	ri, err := k8sutils.GetResourceInterface(dc, m, false, namespace)
+	if err != nil {
+		diagnostics.AddError("Failed to configure resource interface.", err.Error())
+		return nil, diagnostics
+	}
+
+	return ri, diagnostics
+}
@@ -24,6 +24,7 @@ func TestAccResourcesDataSource(t *testing.T) {
 					ConfigStateChecks: []statecheck.StateCheck{
 						statecheck.ExpectKnownValue("data.k8s_resources.test", tfjsonpath.New("objects"), knownvalue.ListSizeExact(1)),
 						statecheck.ExpectKnownValue("data.k8s_resources.test", tfjsonpath.New("objects").AtSliceIndex(0).AtMapKey("kind"), knownvalue.StringExact("Node")),
+						statecheck.ExpectKnownValue("data.k8s_resources.test", tfjsonpath.New("ready_status").AtSliceIndex(0).AtMapKey("status"), knownvalue.StringExact("Current")),
 					},
 				},
 			},
@@ -107,6 +108,7 @@ func TestAccResourcesDataSource(t *testing.T) {
 }`,
 					ConfigStateChecks: []statecheck.StateCheck{
 						statecheck.ExpectKnownValue("data.k8s_resources.test", tfjsonpath.New("objects"), knownvalue.ListSizeExact(0)),
+						statecheck.ExpectKnownValue("data.k8s_resources.test", tfjsonpath.New("ready_status"), knownvalue.ListSizeExact(0)),
 					},
 				},
 			},
@@ -131,4 +133,24 @@ func TestAccResourcesDataSource(t *testing.T) {
 			},
 		})
 	})
+	t.Run("typed_object", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `data "k8s_resources" "test" {
+  api_version    = "rbac.authorization.k8s.io/v1"
+  kind           = "ClusterRole"
+  field_selector = "metadata.name=cluster-admin"
+  typed_object   = true
+}`,
+					ConfigStateChecks: []statecheck.StateCheck{
+						statecheck.ExpectKnownValue("data.k8s_resources.test", tfjsonpath.New("objects"), knownvalue.ListSizeExact(1)),
+						statecheck.ExpectKnownValue("data.k8s_resources.test", tfjsonpath.New("objects").AtSliceIndex(0).AtMapKey("rules").AtSliceIndex(0).AtMapKey("verbs"), knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("*")})),
+					},
+				},
+			},
+		})
+	})
 }
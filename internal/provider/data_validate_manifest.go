@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &ValidateManifestDataSource{}
+	_ datasource.DataSourceWithConfigure = &ValidateManifestDataSource{}
+)
+
+// NewValidateManifestDataSource creates a new validate manifest data source.
+func NewValidateManifestDataSource() datasource.DataSource {
+	return &ValidateManifestDataSource{}
+}
+
+// ValidateManifestDataSource defines the data source implementation.
+type ValidateManifestDataSource struct {
+	providerData *K8sProviderData
+}
+
+// ValidateManifestDataSourceModel describes the data source data model.
+type ValidateManifestDataSourceModel struct {
+	Manifest types.Dynamic `tfsdk:"manifest"`
+}
+
+// Metadata returns the data source metadata.
+func (d *ValidateManifestDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_validate_manifest", req.ProviderTypeName)
+}
+
+// Schema returns the data source schema.
+func (d *ValidateManifestDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "_Kubernetes_ manifest validation TF data source; the manifest is validated against the OpenAPI v3 schema of its kind published by the API server, so errors are reported when planning instead of when applying. The field types, required fields and enum values are checked, as are unknown fields of objects which don't allow additional properties. The data source is read when planning if the manifest is known, otherwise it's read when applying.",
+		Attributes: map[string]schema.Attribute{
+			"manifest": schema.DynamicAttribute{
+				MarkdownDescription: "Manifest to validate; this must contain `apiVersion` and `kind`, and the kind must be known to the API server.",
+				Required:            true,
+			},
+		},
+	}
+}
+
+// Configure configures the data source.
+func (d *ValidateManifestDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*K8sProviderData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected data source provider data.", fmt.Sprintf("expected *K8sProviderData, got: %T", req.ProviderData))
+		return
+	}
+
+	d.providerData = providerData
+}
+
+// Read reads the data source.
+func (d *ValidateManifestDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ValidateManifestDataSourceModel
+
+	if resp.Diagnostics.Append(req.Config.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	if resp.Diagnostics.Append(validateManifest(ctx, d.providerData.Client, path.Root("manifest"), data.Manifest)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
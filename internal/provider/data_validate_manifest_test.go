@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccValidateManifestDataSource(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `data "k8s_validate_manifest" "test" {
+  manifest = {
+    apiVersion = "v1"
+    kind       = "ConfigMap"
+    metadata = {
+      name      = "test"
+      namespace = "default"
+    }
+    data = {
+      foo = "bar"
+    }
+  }
+}`,
+				},
+			},
+		})
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `data "k8s_validate_manifest" "test" {
+  manifest = {
+    apiVersion = "apps/v1"
+    kind       = "Deployment"
+    metadata = {
+      name      = "test"
+      namespace = "default"
+    }
+    spec = {
+      replica = 1
+      selector = {
+        matchLabels = {
+          app = "test"
+        }
+      }
+      template = {
+        metadata = {
+          labels = {
+            app = "test"
+          }
+        }
+        spec = {
+          containers = [
+            {
+              name  = "test"
+              image = "nginx:latest"
+            }
+          ]
+        }
+      }
+    }
+  }
+}`,
+					ExpectError: regexp.MustCompile(`spec.replica: unknown field`),
+				},
+			},
+		})
+	})
+}
@@ -1,13 +1,58 @@
 package provider
 
 import (
+	"sync/atomic"
+
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/openapi"
 )
 
 type discoveryClientStub struct {
 	discovery.CachedDiscoveryInterface
+
+	openAPIClient openapi.Client
+	serverVersion *version.Info
+}
+
+func (c *discoveryClientStub) OpenAPIV3() openapi.Client {
+	return c.openAPIClient
+}
+
+func (c *discoveryClientStub) ServerVersion() (*version.Info, error) {
+	return c.serverVersion, nil
+}
+
+type openAPIClientStub struct {
+	openapi.Client
+
+	fetches atomic.Int32
+}
+
+func (c *openAPIClientStub) Paths() (map[string]openapi.GroupVersion, error) {
+	paths, err := c.Client.Paths()
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range paths {
+		paths[k] = &openAPIGroupVersionStub{GroupVersion: v, fetches: &c.fetches}
+	}
+
+	return paths, nil
+}
+
+type openAPIGroupVersionStub struct {
+	openapi.GroupVersion
+
+	fetches *atomic.Int32
+}
+
+func (gv *openAPIGroupVersionStub) Schema(contentType string) ([]byte, error) {
+	gv.fetches.Add(1)
+	return gv.GroupVersion.Schema(contentType)
 }
 
 type dynamicClientStub struct {
@@ -16,4 +61,10 @@ type dynamicClientStub struct {
 
 type restMapperStub struct {
 	meta.ResettableRESTMapper
+
+	resets int
+}
+
+func (m *restMapperStub) Reset() {
+	m.resets++
 }
@@ -0,0 +1,209 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/terr4m/terraform-provider-k8s/internal/k8sutils"
+	"github.com/terr4m/terraform-provider-k8s/internal/tfutils"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// listPageSize is the maximum number of resources requested from the API server in a single list call.
+const listPageSize int64 = 500
+
+var (
+	_ list.ListResource              = &ResourceListResource{}
+	_ list.ListResourceWithConfigure = &ResourceListResource{}
+)
+
+// NewResourceListResource creates a new resource list resource.
+func NewResourceListResource() list.ListResource {
+	return &ResourceListResource{}
+}
+
+// ResourceListResource defines the list resource implementation.
+type ResourceListResource struct {
+	providerData *K8sProviderData
+}
+
+// ResourceListResourceModel describes the list resource data model.
+type ResourceListResourceModel struct {
+	APIVersion    types.String `tfsdk:"api_version"`
+	Kind          types.String `tfsdk:"kind"`
+	Namespace     types.String `tfsdk:"namespace"`
+	FieldSelector types.String `tfsdk:"field_selector"`
+	LabelSelector types.String `tfsdk:"label_selector"`
+}
+
+// Metadata returns the list resource metadata.
+func (l *ResourceListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_resource", req.ProviderTypeName)
+}
+
+// ListResourceConfigSchema returns the list resource schema.
+func (l *ResourceListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "_Kubernetes_ resource TF list resource.",
+		Attributes: map[string]schema.Attribute{
+			"api_version": schema.StringAttribute{
+				MarkdownDescription: "API version of the resources to list.",
+				Required:            true,
+			},
+			"kind": schema.StringAttribute{
+				MarkdownDescription: "Kind of the resources to list.",
+				Required:            true,
+			},
+			"namespace": schema.StringAttribute{
+				MarkdownDescription: "Namespace of the resources to list.",
+				Optional:            true,
+			},
+			"field_selector": schema.StringAttribute{
+				MarkdownDescription: "Field selector for the resources to list.",
+				Optional:            true,
+			},
+			"label_selector": schema.StringAttribute{
+				MarkdownDescription: "Label selector for the resources to list.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+// Configure configures the list resource.
+func (l *ResourceListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*K8sProviderData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected list resource provider data.", fmt.Sprintf("expected *K8sProviderData, got: %T", req.ProviderData))
+		return
+	}
+
+	l.providerData = providerData
+}
+
+// List lists the resources; the manifest of each resource is made up of the fields owned by the provider field manager,
+// or only of its identity if the field manager doesn't own any fields, matching the import. The resources are listed in
+// pages until the requested limit is reached.
+func (l *ResourceListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var data ResourceListResourceModel
+
+	if diags := req.Config.Get(ctx, &data); diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	ri, diags := listResourceInterface(l.providerData.Client, data.APIVersion.ValueString(), data.Kind.ValueString(), data.Namespace.ValueString())
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	opts := metav1.ListOptions{
+		FieldSelector: data.FieldSelector.ValueString(),
+		LabelSelector: data.LabelSelector.ValueString(),
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		ctx, cancel := context.WithTimeout(ctx, l.providerData.DefaultTimeouts.Read)
+		defer cancel()
+
+		var listed int64
+		var unowned int
+		for {
+			opts.Limit = listPageSize
+			if req.Limit > 0 {
+				opts.Limit = min(req.Limit-listed, listPageSize)
+			}
+
+			ul, err := ri.List(ctx, opts)
+			if err != nil {
+				var diags diag.Diagnostics
+				diags.AddError("Failed to list resources.", err.Error())
+				push(list.ListResult{Diagnostics: diags})
+				return
+			}
+
+			for i := range ul.Items {
+				result := req.NewListResult(ctx)
+				result.DisplayName = displayName(&ul.Items[i])
+				result.Diagnostics.Append(result.Identity.Set(ctx, newResourceResourceIdentityModel(&ul.Items[i]))...)
+
+				if req.IncludeResource && !result.Diagnostics.HasError() {
+					owned, diags := l.setResource(ctx, &ul.Items[i], result.Resource)
+					result.Diagnostics.Append(diags...)
+
+					if !owned {
+						unowned++
+					}
+				}
+
+				if !push(result) {
+					return
+				}
+
+				listed++
+			}
+
+			opts.Continue = ul.GetContinue()
+			if len(opts.Continue) == 0 || (req.Limit > 0 && listed >= req.Limit) {
+				break
+			}
+		}
+
+		if unowned > 0 {
+			var diags diag.Diagnostics
+			diags.AddWarning("No managed fields.", fmt.Sprintf("the field manager %q doesn't own any fields of %d of the listed resources; their manifests only contain the resource identity", l.providerData.FieldManager.Name, unowned))
+			push(list.ListResult{Diagnostics: diags})
+		}
+	}
+}
+
+// setResource sets the resource state for the listed object and returns true if the provider field manager owns any of
+// its fields.
+func (l *ResourceListResource) setResource(ctx context.Context, obj *unstructured.Unstructured, res *tfsdk.Resource) (bool, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	fields, owned, err := k8sutils.ExtractManagedFields(obj, l.providerData.FieldManager.Name)
+	if err != nil {
+		diagnostics.AddError("Failed to extract managed fields.", err.Error())
+		return false, diagnostics
+	}
+
+	manifest, diags := tfutils.DecodeDynamic(ctx, fields)
+	if diagnostics.Append(diags...); diagnostics.HasError() {
+		return owned, diagnostics
+	}
+
+	result, diags := decodeObject(ctx, obj)
+	if diagnostics.Append(diags...); diagnostics.HasError() {
+		return owned, diagnostics
+	}
+
+	diagnostics.Append(res.SetAttribute(ctx, path.Root("manifest"), manifest)...)
+	diagnostics.Append(res.SetAttribute(ctx, path.Root("result"), result)...)
+
+	return owned, diagnostics
+}
+
+// displayName returns a human readable name for the object.
+func displayName(obj *unstructured.Unstructured) string {
+	if len(obj.GetNamespace()) == 0 {
+		return obj.GetName()
+	}
+
+	return fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+)
+
+func TestAccResourceListResource(t *testing.T) {
+	namespace := "default"
+	name := "tf-acc-list-resource"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`resource "k8s_resource" "test" {
+  count = 2
+
+  manifest = {
+    apiVersion = "v1"
+    kind       = "ConfigMap"
+    metadata = {
+      namespace = "%s"
+      name      = "%s-${count.index}"
+      labels = {
+        "tf-acc-test" = "list"
+      }
+    }
+  }
+}`, namespace, name),
+			},
+			{
+				Query: true,
+				Config: fmt.Sprintf(`provider "k8s" {}
+
+list "k8s_resource" "test" {
+  provider = k8s
+
+  config {
+    api_version    = "v1"
+    kind           = "ConfigMap"
+    namespace      = "%s"
+    label_selector = "tf-acc-test=list"
+  }
+}`, namespace),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("k8s_resource.test", 2),
+					querycheck.ExpectIdentity("k8s_resource.test", map[string]knownvalue.Check{
+						"api_version": knownvalue.StringExact("v1"),
+						"kind":        knownvalue.StringExact("ConfigMap"),
+						"namespace":   knownvalue.StringExact(namespace),
+						"name":        knownvalue.StringExact(name + "-0"),
+					}),
+				},
+			},
+			{
+				Query: true,
+				Config: fmt.Sprintf(`provider "k8s" {}
+
+list "k8s_resource" "test" {
+  provider = k8s
+  limit    = 1
+
+  config {
+    api_version    = "v1"
+    kind           = "ConfigMap"
+    namespace      = "%s"
+    label_selector = "tf-acc-test=list"
+  }
+}`, namespace),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("k8s_resource.test", 1),
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,333 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/terr4m/terraform-provider-k8s/internal/k8sutils"
+	"github.com/terr4m/terraform-provider-k8s/internal/tfutils"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+)
+
+// manifestIdentity identifies the object described by a manifest.
+type manifestIdentity struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+}
+
+// getManifestIdentity returns the identity of the object described by the manifest; ok is false if any of the identity values are unknown.
+func getManifestIdentity(manifest types.Dynamic) (manifestIdentity, bool) {
+	var id manifestIdentity
+
+	for _, f := range []struct {
+		path   []string
+		target *string
+	}{
+		{path: []string{"apiVersion"}, target: &id.APIVersion},
+		{path: []string{"kind"}, target: &id.Kind},
+		{path: []string{"metadata", "namespace"}, target: &id.Namespace},
+		{path: []string{"metadata", "name"}, target: &id.Name},
+	} {
+		var v attr.Value = manifest
+		for _, k := range f.path {
+			v = getAttribute(v, k)
+		}
+
+		if v == nil || v.IsNull() {
+			continue
+		}
+
+		if v.IsUnknown() {
+			return id, false
+		}
+
+		if s, ok := v.(types.String); ok {
+			*f.target = s.ValueString()
+		}
+	}
+
+	return id, true
+}
+
+// getAttribute returns the named attribute of an object or map value, or nil if it doesn't exist.
+func getAttribute(v attr.Value, name string) attr.Value {
+	if d, ok := v.(types.Dynamic); ok {
+		if d.IsNull() || d.IsUnknown() {
+			return d
+		}
+		v = d.UnderlyingValue()
+	}
+
+	if v == nil || v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+
+	switch vv := v.(type) {
+	case types.Object:
+		return vv.Attributes()[name]
+	case types.Map:
+		return vv.Elements()[name]
+	default:
+		return nil
+	}
+}
+
+// manifestToUnstructured encodes a manifest into an unstructured object.
+func manifestToUnstructured(ctx context.Context, manifest types.Dynamic) (*unstructured.Unstructured, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	o, diags := tfutils.EncodeDynamic(ctx, path.Root("manifest"), manifest)
+	if diagnostics.Append(diags...); diagnostics.HasError() {
+		return nil, diagnostics
+	}
+
+	m, ok := o.(map[string]any)
+	if !ok {
+		diagnostics.AddError("Invalid manifest.", fmt.Sprintf("expected an object, got: %T", o))
+		return nil, diagnostics
+	}
+
+	obj := &unstructured.Unstructured{Object: m}
+
+	if len(obj.GetAPIVersion()) == 0 || len(obj.GetKind()) == 0 || len(obj.GetName()) == 0 {
+		diagnostics.AddError("Invalid manifest.", "the manifest must contain apiVersion, kind and metadata.name")
+		return nil, diagnostics
+	}
+
+	return obj, diagnostics
+}
+
+// validateManifest validates the manifest against the OpenAPI schema of its kind; the diagnostics are reported against
+// the attribute path of each invalid field, relative to the given path of the manifest.
+func validateManifest(ctx context.Context, client *K8sProviderClient, p path.Path, manifest types.Dynamic) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	o, diags := tfutils.EncodeDynamic(ctx, p, manifest)
+	if diagnostics.Append(diags...); diagnostics.HasError() {
+		return diagnostics
+	}
+
+	m, ok := o.(map[string]any)
+	if !ok {
+		diagnostics.AddAttributeError(p, "Invalid manifest.", fmt.Sprintf("expected an object, got: %T", o))
+		return diagnostics
+	}
+
+	obj := &unstructured.Unstructured{Object: m}
+	if len(obj.GetAPIVersion()) == 0 || len(obj.GetKind()) == 0 {
+		diagnostics.AddAttributeError(p, "Invalid manifest.", "the manifest must contain apiVersion and kind")
+		return diagnostics
+	}
+
+	gvk, err := k8sutils.ParseGVK(obj.GetAPIVersion(), obj.GetKind())
+	if err != nil {
+		diagnostics.AddAttributeError(p.AtName("apiVersion"), "Failed to parse GVK.", err.Error())
+		return diagnostics
+	}
+
+	s, err := client.OpenAPISchema(*gvk)
+	if err != nil {
+		diagnostics.AddAttributeError(p, "Failed to get OpenAPI schema.", err.Error())
+		return diagnostics
+	}
+
+	for _, e := range k8sutils.ValidateObject(m, s) {
+		diagnostics.AddAttributeError(manifestFieldPath(p, manifest, e.Path), "Invalid manifest field.", e.Error())
+	}
+
+	return diagnostics
+}
+
+// manifestFieldPath returns the attribute path of a manifest field relative to the given path; field names are added as
+// attribute names or map keys, depending on the type of the manifest value. The path stops at the last element which
+// exists in the manifest, so missing fields are reported against their parent.
+func manifestFieldPath(p path.Path, v attr.Value, fieldPath []any) path.Path {
+	for _, e := range fieldPath {
+		if d, ok := v.(types.Dynamic); ok {
+			v = d.UnderlyingValue()
+		}
+
+		var next attr.Value
+		var np path.Path
+		switch vv := v.(type) {
+		case types.Object:
+			if name, ok := e.(string); ok {
+				next, np = vv.Attributes()[name], p.AtName(name)
+			}
+		case types.Map:
+			if name, ok := e.(string); ok {
+				next, np = vv.Elements()[name], p.AtMapKey(name)
+			}
+		case types.Tuple:
+			if i, ok := e.(int); ok && i < len(vv.Elements()) {
+				next, np = vv.Elements()[i], p.AtListIndex(i)
+			}
+		case types.List:
+			if i, ok := e.(int); ok && i < len(vv.Elements()) {
+				next, np = vv.Elements()[i], p.AtListIndex(i)
+			}
+		}
+
+		if next == nil {
+			return p
+		}
+
+		v, p = next, np
+	}
+
+	return p
+}
+
+// getObjectResourceInterface returns a dynamic resource interface for the given object.
+func getObjectResourceInterface(client *K8sProviderClient, obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	return objectResourceInterface(client, obj, func(rm meta.ResettableRESTMapper, gvk *schema.GroupVersionKind) (*meta.RESTMapping, error) {
+		return k8sutils.GetMapping(rm, gvk)
+	})
+}
+
+// waitForObjectResourceInterface returns a dynamic resource interface for the given object; if the object kind isn't
+// known the REST mapper is reset and the mapping retried for up to about 30 seconds, as the kind might be defined by a
+// custom resource definition created in the same operation.
+func waitForObjectResourceInterface(ctx context.Context, client *K8sProviderClient, obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	return objectResourceInterface(client, obj, func(rm meta.ResettableRESTMapper, gvk *schema.GroupVersionKind) (*meta.RESTMapping, error) {
+		return k8sutils.GetMappingWithRetry(ctx, rm, gvk, k8sutils.DefaultMappingBackoff)
+	})
+}
+
+// objectResourceInterface returns a dynamic resource interface for the given object using the mapping function; if the
+// object is namespaced but doesn't set a namespace the default namespace of the client is used.
+func objectResourceInterface(client *K8sProviderClient, obj *unstructured.Unstructured, getMapping func(meta.ResettableRESTMapper, *schema.GroupVersionKind) (*meta.RESTMapping, error)) (dynamic.ResourceInterface, error) {
+	gvk, err := k8sutils.ParseGVK(obj.GetAPIVersion(), obj.GetKind())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse gvk: %w", err)
+	}
+
+	rm, err := client.RESTMapper()
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := getMapping(rm, gvk)
+	if err != nil {
+		return nil, err
+	}
+
+	dc, err := client.DynamicClient()
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := obj.GetNamespace()
+	if len(namespace) == 0 {
+		namespace = client.defaultNamespace
+	}
+
+	return k8sutils.GetResourceInterface(dc, m, true, namespace)
+}
+
+// decodeObject decodes an object returned by the API server into a Terraform dynamic value without the volatile fields;
+// empty objects and lists are kept so the result mirrors the API object.
+func decodeObject(ctx context.Context, obj *unstructured.Unstructured) (types.Dynamic, diag.Diagnostics) {
+	return tfutils.DecodeDynamic(ctx, k8sutils.RemoveVolatileFields(obj.Object), tfutils.WithEmptyCollections())
+}
+
+// decodeTypedObject decodes an object in the same way as decodeObject, but using the OpenAPI schema of its kind so the
+// types of lists and maps are consistent.
+func decodeTypedObject(ctx context.Context, client *K8sProviderClient, obj *unstructured.Unstructured) (types.Dynamic, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	s, err := client.OpenAPISchema(obj.GroupVersionKind())
+	if err != nil {
+		diagnostics.AddError("Failed to get OpenAPI schema.", err.Error())
+		return types.Dynamic{}, diagnostics
+	}
+
+	return tfutils.DecodeDynamicWithSchema(ctx, k8sutils.RemoveVolatileFields(obj.Object), s.Schema, s.Resolve, tfutils.WithEmptyCollections())
+}
+
+// waitForObject watches the object until it satisfies the wait condition or the context is done; if the wait fails the
+// diagnostics contain the last observed status of the object.
+func waitForObject(ctx context.Context, ri dynamic.ResourceInterface, obj *unstructured.Unstructured, cond *k8sutils.WaitCondition) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", obj.GetName()).String()
+	lw := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			opts.FieldSelector = fieldSelector
+			return ri.List(ctx, opts)
+		},
+		WatchFuncWithContext: func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			opts.FieldSelector = fieldSelector
+			return ri.Watch(ctx, opts)
+		},
+	}
+
+	var last *unstructured.Unstructured
+	msg := "resource not observed"
+	_, err := watchtools.UntilWithSync(ctx, lw, &unstructured.Unstructured{}, nil, func(e watch.Event) (bool, error) {
+		switch e.Type {
+		case watch.Added, watch.Modified:
+			o, ok := e.Object.(*unstructured.Unstructured)
+			if !ok {
+				return false, nil
+			}
+			last = o
+
+			done, m, err := cond.Check(o)
+			msg = m
+			return done, err
+		case watch.Deleted:
+			return false, fmt.Errorf("resource was deleted")
+		default:
+			return false, nil
+		}
+	})
+	if err == nil {
+		return diagnostics
+	}
+
+	if ctx.Err() != nil {
+		err = fmt.Errorf("timed out waiting for resource: %s", msg)
+	}
+
+	diagnostics.AddError("Failed to wait for resource.", fmt.Sprintf("%s; last observed status: %s", err.Error(), lastObservedStatus(last)))
+
+	return diagnostics
+}
+
+// lastObservedStatus returns the JSON representation of the object status.
+func lastObservedStatus(obj *unstructured.Unstructured) string {
+	if obj == nil {
+		return "<none>"
+	}
+
+	status, ok := obj.Object["status"]
+	if !ok {
+		return "<none>"
+	}
+
+	b, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Sprint(status)
+	}
+
+	return string(b)
+}
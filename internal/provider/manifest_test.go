@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestManifestFieldPath(t *testing.T) {
+	t.Parallel()
+
+	containerType := map[string]attr.Type{"name": types.StringType}
+	containers := types.TupleValueMust(
+		[]attr.Type{types.ObjectType{AttrTypes: containerType}},
+		[]attr.Value{types.ObjectValueMust(containerType, map[string]attr.Value{"name": types.StringValue("foo")})},
+	)
+	labels := types.MapValueMust(types.StringType, map[string]attr.Value{"app": types.StringValue("foo")})
+
+	manifest := types.DynamicValue(types.ObjectValueMust(
+		map[string]attr.Type{"labels": labels.Type(t.Context()), "containers": containers.Type(t.Context())},
+		map[string]attr.Value{"labels": labels, "containers": containers},
+	))
+
+	for _, d := range []struct {
+		testName  string
+		fieldPath []any
+		want      path.Path
+	}{
+		{
+			testName:  "root",
+			fieldPath: nil,
+			want:      path.Root("manifest"),
+		},
+		{
+			testName:  "object_attribute",
+			fieldPath: []any{"containers", 0, "name"},
+			want:      path.Root("manifest").AtName("containers").AtListIndex(0).AtName("name"),
+		},
+		{
+			testName:  "map_key",
+			fieldPath: []any{"labels", "app"},
+			want:      path.Root("manifest").AtName("labels").AtMapKey("app"),
+		},
+		{
+			testName:  "missing_field",
+			fieldPath: []any{"containers", 0, "image"},
+			want:      path.Root("manifest").AtName("containers").AtListIndex(0),
+		},
+		{
+			testName:  "missing_index",
+			fieldPath: []any{"containers", 1, "name"},
+			want:      path.Root("manifest").AtName("containers"),
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := manifestFieldPath(path.Root("manifest"), manifest, d.fieldPath); !got.Equal(d.want) {
+				t.Errorf("manifestFieldPath() returned %s, want %s", got, d.want)
+			}
+		})
+	}
+}
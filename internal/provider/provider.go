@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -19,6 +20,7 @@ var (
 	_ provider.Provider                       = &K8sProvider{}
 	_ provider.ProviderWithFunctions          = &K8sProvider{}
 	_ provider.ProviderWithEphemeralResources = &K8sProvider{}
+	_ provider.ProviderWithListResources      = &K8sProvider{}
 )
 
 // New returns a new provider implementation.
@@ -56,31 +58,74 @@ type Timeouts struct {
 
 // K8sProviderModel describes the provider data model.
 type K8sProviderModel struct {
-	Host                  types.String       `tfsdk:"host"`
-	Username              types.String       `tfsdk:"username"`
-	Password              types.String       `tfsdk:"password"`
-	Insecure              types.Bool         `tfsdk:"insecure"`
-	TLSServerName         types.String       `tfsdk:"tls_server_name"`
-	ClientCertificate     types.String       `tfsdk:"client_certificate"`
-	ClientKey             types.String       `tfsdk:"client_key"`
-	ClusterCACertificate  types.String       `tfsdk:"cluster_ca_certificate"`
-	ConfigPaths           types.List         `tfsdk:"config_paths"`
-	ConfigContext         types.String       `tfsdk:"config_context"`
-	ConfigContextAuthInfo types.String       `tfsdk:"config_context_auth_info"`
-	ConfigContextCluster  types.String       `tfsdk:"config_context_cluster"`
-	Token                 types.String       `tfsdk:"token"`
-	ProxyURL              types.String       `tfsdk:"proxy_url"`
-	Exec                  *ExecConfigModel   `tfsdk:"exec"`
-	FieldManager          *FieldManagerModel `tfsdk:"field_manager"`
-	Timeouts              timeouts.Value     `tfsdk:"timeouts"`
+	Host                  types.String         `tfsdk:"host"`
+	Username              types.String         `tfsdk:"username"`
+	Password              types.String         `tfsdk:"password"`
+	Insecure              types.Bool           `tfsdk:"insecure"`
+	TLSServerName         types.String         `tfsdk:"tls_server_name"`
+	ClientCertificate     types.String         `tfsdk:"client_certificate"`
+	ClientKey             types.String         `tfsdk:"client_key"`
+	ClusterCACertificate  types.String         `tfsdk:"cluster_ca_certificate"`
+	InCluster             types.Bool           `tfsdk:"in_cluster"`
+	ConfigPaths           types.List           `tfsdk:"config_paths"`
+	ConfigRaw             types.String         `tfsdk:"config_raw"`
+	ConfigContext         types.String         `tfsdk:"config_context"`
+	ConfigContextAuthInfo types.String         `tfsdk:"config_context_auth_info"`
+	ConfigContextCluster  types.String         `tfsdk:"config_context_cluster"`
+	Token                 types.String         `tfsdk:"token"`
+	Impersonate           *ImpersonateModel    `tfsdk:"impersonate"`
+	ProxyURL              types.String         `tfsdk:"proxy_url"`
+	QPS                   types.Float64        `tfsdk:"qps"`
+	Burst                 types.Int64          `tfsdk:"burst"`
+	Retry                 *RetryModel          `tfsdk:"retry"`
+	OpenAPICacheDir       types.String         `tfsdk:"openapi_cache_dir"`
+	DiscoveryCache        *DiscoveryCacheModel `tfsdk:"discovery_cache"`
+	Exec                  *ExecConfigModel     `tfsdk:"exec"`
+	OIDC                  *OIDCModel           `tfsdk:"oidc"`
+	FieldManager          *FieldManagerModel   `tfsdk:"field_manager"`
+	Timeouts              timeouts.Value       `tfsdk:"timeouts"`
+}
+
+// ImpersonateModel configures the user and groups to impersonate.
+type ImpersonateModel struct {
+	User   types.String `tfsdk:"user"`
+	UID    types.String `tfsdk:"uid"`
+	Groups types.List   `tfsdk:"groups"`
+	Extra  types.Map    `tfsdk:"extra"`
+}
+
+// RetryModel configures retrying failed API requests.
+type RetryModel struct {
+	MaxAttempts types.Int64  `tfsdk:"max_attempts"`
+	MinBackoff  types.String `tfsdk:"min_backoff"`
+	MaxBackoff  types.String `tfsdk:"max_backoff"`
+}
+
+// DiscoveryCacheModel configures the on disk discovery cache.
+type DiscoveryCacheModel struct {
+	Dir types.String `tfsdk:"dir"`
+	TTL types.String `tfsdk:"ttl"`
 }
 
 // ExecConfigModel configures an external command to configure the Kubernetes client.
 type ExecConfigModel struct {
-	APIVersion types.String `tfsdk:"api_version"`
-	Command    types.String `tfsdk:"command"`
-	Env        types.Map    `tfsdk:"env"`
-	Args       types.List   `tfsdk:"args"`
+	APIVersion         types.String `tfsdk:"api_version"`
+	Command            types.String `tfsdk:"command"`
+	Env                types.Map    `tfsdk:"env"`
+	Args               types.List   `tfsdk:"args"`
+	InteractiveMode    types.String `tfsdk:"interactive_mode"`
+	ProvideClusterInfo types.Bool   `tfsdk:"provide_cluster_info"`
+	InstallHint        types.String `tfsdk:"install_hint"`
+}
+
+// OIDCModel configures OIDC authentication.
+type OIDCModel struct {
+	IssuerURL     types.String `tfsdk:"issuer_url"`
+	ClientID      types.String `tfsdk:"client_id"`
+	ClientSecret  types.String `tfsdk:"client_secret"`
+	RefreshToken  types.String `tfsdk:"refresh_token"`
+	IDToken       types.String `tfsdk:"id_token"`
+	CACertificate types.String `tfsdk:"ca_certificate"`
 }
 
 // FieldManagerModel configures the field manager.
@@ -138,11 +183,20 @@ func (p *K8sProvider) Schema(ctx context.Context, _ provider.SchemaRequest, resp
 				MarkdownDescription: "PEM-encoded root certificates bundle for TLS authentication. Can be set with the `KUBE_CLUSTER_CA_CERT_DATA` environment variable.",
 				Optional:            true,
 			},
+			"in_cluster": schema.BoolAttribute{
+				MarkdownDescription: "If `true`, the provider authenticates with the service account mounted into the pod it's running in, re-reading the token when it's rotated, and namespaced objects which don't set `metadata.namespace` use the namespace of the service account; the connection and authentication attributes other than `impersonate`, `qps`, `burst` and `retry`, and their environment variables, can't be set. If not set this defaults to `true` when the `KUBERNETES_SERVICE_HOST` environment variable is set and none of those attributes or environment variables are configured. Can be set with the `KUBE_IN_CLUSTER` environment variable.",
+				Optional:            true,
+			},
 			"config_paths": schema.ListAttribute{
 				MarkdownDescription: "List of paths to the kube config file. Can be set with the `KUBE_CONFIG_PATHS` environment variable.",
 				ElementType:         types.StringType,
 				Optional:            true,
 			},
+			"config_raw": schema.StringAttribute{
+				MarkdownDescription: "Raw kube config content, which is used instead of reading the kube config from disk; this can't be set together with `config_paths`. The context, cluster and authentication info are selected with `config_context`, `config_context_cluster` and `config_context_auth_info`. Can be set with the `KUBE_CONFIG_RAW` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
 			"config_context": schema.StringAttribute{
 				MarkdownDescription: "Context to choose from the kube config file. Can be set with the `KUBE_CTX`environment variable.",
 				Optional:            true,
@@ -159,12 +213,66 @@ func (p *K8sProvider) Schema(ctx context.Context, _ provider.SchemaRequest, resp
 				MarkdownDescription: "Token to authenticate a service account. Can be set with the `KUBE_TOKEN` environment variable.",
 				Optional:            true,
 			},
+			"impersonate": schema.SingleNestedAttribute{
+				MarkdownDescription: "Impersonation configuration; if set all requests are made as the impersonated user, so they're authorized with the RBAC of that user instead of the authenticated one.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"user": schema.StringAttribute{
+						MarkdownDescription: "Name of the user to impersonate. Can be set with the `KUBE_IMPERSONATE_USER` environment variable.",
+						Optional:            true,
+					},
+					"uid": schema.StringAttribute{
+						MarkdownDescription: "UID of the user to impersonate. Can be set with the `KUBE_IMPERSONATE_UID` environment variable.",
+						Optional:            true,
+					},
+					"groups": schema.ListAttribute{
+						MarkdownDescription: "Groups to impersonate. Can be set with the `KUBE_IMPERSONATE_GROUPS` environment variable as a comma separated list.",
+						ElementType:         types.StringType,
+						Optional:            true,
+					},
+					"extra": schema.MapAttribute{
+						MarkdownDescription: "Extra fields of the user to impersonate. Can be set with the `KUBE_IMPERSONATE_EXTRA` environment variable as a comma separated list of `key=value` pairs, where a key can be repeated to set multiple values.",
+						ElementType:         types.ListType{ElemType: types.StringType},
+						Optional:            true,
+					},
+				},
+			},
 			"proxy_url": schema.StringAttribute{
 				MarkdownDescription: "URL to the proxy to be used for all API requests. Can be set with the `KUBE_PROXY_URL` environment variable.",
 				Optional:            true,
 			},
+			"qps": schema.Float64Attribute{
+				MarkdownDescription: "Maximum number of queries per second to the _Kubernetes_ API server, allowing for bursts up to `burst`; defaults to `5`. Can be set with the `KUBE_QPS` environment variable.",
+				Optional:            true,
+			},
+			"burst": schema.Int64Attribute{
+				MarkdownDescription: "Maximum burst of queries to the _Kubernetes_ API server when throttling with `qps`; defaults to `10`. Can be set with the `KUBE_BURST` environment variable.",
+				Optional:            true,
+			},
+			"retry": schema.SingleNestedAttribute{
+				MarkdownDescription: "Retry configuration for failed _Kubernetes_ API requests; if set requests failing with a `429` status are retried, and idempotent requests (`GET`, `HEAD`, `PUT` and server-side apply `PATCH` requests) failing with a connection reset or a `5xx` status are also retried, waiting for the duration of the `Retry-After` header if it's present or backing off exponentially otherwise. The _Kubernetes_ client also retries `429` and `5xx` responses which have a `Retry-After` header up to 10 times, so for those responses the total number of attempts can be up to 10 times `max_attempts`.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						MarkdownDescription: "Maximum number of attempts for a request, including the first one; defaults to `3`.",
+						Optional:            true,
+					},
+					"min_backoff": schema.StringAttribute{
+						MarkdownDescription: "Delay before the first retry, which is doubled for each retry after that; defaults to `1s`. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
+						Optional:            true,
+					},
+					"max_backoff": schema.StringAttribute{
+						MarkdownDescription: "Maximum delay between attempts, including delays requested by the `Retry-After` header; defaults to `30s`. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
+						Optional:            true,
+					},
+				},
+			},
+			"openapi_cache_dir": schema.StringAttribute{
+				MarkdownDescription: "Directory to cache the _Kubernetes_ OpenAPI v3 documents in, keyed by the server host and version; if not set the documents are only cached in memory. Can be set with the `KUBE_OPENAPI_CACHE_DIR` environment variable.",
+				Optional:            true,
+			},
 			"exec": schema.SingleNestedAttribute{
-				MarkdownDescription: "Exec configuration for Kubernetes authentication",
+				MarkdownDescription: "Exec configuration for Kubernetes authentication; the credential returned by the exec plugin is cached and shared by all the provider clients until it expires.",
 				Optional:            true,
 				Attributes: map[string]schema.Attribute{
 					"api_version": schema.StringAttribute{
@@ -185,6 +293,65 @@ func (p *K8sProvider) Schema(ctx context.Context, _ provider.SchemaRequest, resp
 						ElementType:         types.StringType,
 						Optional:            true,
 					},
+					"interactive_mode": schema.StringAttribute{
+						MarkdownDescription: "Whether the exec plugin can use the standard input to interact with the user, one of `Never`, `IfAvailable` or `Always`; defaults to `IfAvailable`.",
+						Optional:            true,
+					},
+					"provide_cluster_info": schema.BoolAttribute{
+						MarkdownDescription: "If `true`, the cluster information is passed to the exec plugin in the `KUBERNETES_EXEC_INFO` environment variable.",
+						Optional:            true,
+					},
+					"install_hint": schema.StringAttribute{
+						MarkdownDescription: "Message to show if the exec plugin command can't be found, such as how to install it.",
+						Optional:            true,
+					},
+				},
+			},
+			"oidc": schema.SingleNestedAttribute{
+				MarkdownDescription: "OIDC authentication configuration; the ID token is sent as the bearer token and is refreshed with the refresh token when it's about to expire or a request is rejected with a `401` status. This can't be set together with any other credentials, including those of the kube config user, or when the in-cluster config is used. The token endpoint is discovered from the OpenID configuration of the issuer, using the cluster proxy if one is configured.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"issuer_url": schema.StringAttribute{
+						MarkdownDescription: "URL of the OIDC issuer.",
+						Required:            true,
+					},
+					"client_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the OIDC client.",
+						Required:            true,
+					},
+					"client_secret": schema.StringAttribute{
+						MarkdownDescription: "Secret of the OIDC client; this isn't needed for public clients.",
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"refresh_token": schema.StringAttribute{
+						MarkdownDescription: "Refresh token used to get a new ID token; if not set the ID token can't be refreshed.",
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"id_token": schema.StringAttribute{
+						MarkdownDescription: "Initial ID token; if not set an ID token is requested with the refresh token.",
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"ca_certificate": schema.StringAttribute{
+						MarkdownDescription: "PEM-encoded CA certificate of the OIDC issuer; if not set the system CAs are used.",
+						Optional:            true,
+					},
+				},
+			},
+			"discovery_cache": schema.SingleNestedAttribute{
+				MarkdownDescription: "On disk discovery cache configuration; if set the _Kubernetes_ API discovery information is cached on disk and shared between runs, otherwise it's only cached in memory. The cache is used to resolve the resource kinds and is refreshed if a kind isn't found.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"dir": schema.StringAttribute{
+						MarkdownDescription: "Directory to cache the discovery information in; defaults to `~/.kube/cache`, which is shared with `kubectl`. Can be set with the `KUBE_DISCOVERY_CACHE_DIR` environment variable.",
+						Optional:            true,
+					},
+					"ttl": schema.StringAttribute{
+						MarkdownDescription: "How long the cached discovery information is valid for; defaults to `6h`. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
+						Optional:            true,
+					},
 				},
 			},
 			"field_manager": schema.SingleNestedAttribute{
@@ -236,6 +403,12 @@ func (p *K8sProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		return
 	}
 
+	// Create the client options
+	clientOpts, diags := getClientOptions(model)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Create the field manager config
 	fieldManager := FieldManager{
 		Name:           "terraform-provider-k8s",
@@ -273,7 +446,7 @@ func (p *K8sProvider) Configure(ctx context.Context, req provider.ConfigureReque
 	providerData := &K8sProviderData{
 		provider:     p,
 		Model:        model,
-		Client:       NewK8sProviderClient(restConfig),
+		Client:       NewK8sProviderClient(restConfig, clientOpts...),
 		FieldManager: &fieldManager,
 		DefaultTimeouts: &Timeouts{
 			Create: createTimeout,
@@ -285,11 +458,23 @@ func (p *K8sProvider) Configure(ctx context.Context, req provider.ConfigureReque
 
 	resp.DataSourceData = providerData
 	resp.ResourceData = providerData
+	resp.ListResourceData = providerData
 }
 
 // Resources returns the provider resources.
 func (p *K8sProvider) Resources(_ context.Context) []func() resource.Resource {
-	return []func() resource.Resource{}
+	return []func() resource.Resource{
+		NewResourceResource,
+		NewManifestResource,
+		NewPatchResource,
+	}
+}
+
+// ListResources returns the provider list resources.
+func (p *K8sProvider) ListResources(_ context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewResourceListResource,
+	}
 }
 
 // EphemeralResources returns the provider ephemeral resources.
@@ -303,6 +488,7 @@ func (p *K8sProvider) DataSources(_ context.Context) []func() datasource.DataSou
 		NewResourceDataSource,
 		NewResourcesDataSource,
 		NewServerVersionDataSource,
+		NewValidateManifestDataSource,
 	}
 }
 
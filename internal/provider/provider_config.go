@@ -3,39 +3,73 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/terr4m/terraform-provider-k8s/internal/k8sutils"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/mitchellh/go-homedir"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apimachineryschema "k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
+// serviceAccountNamespaceFile is the file the namespace of the service account is mounted at in a pod.
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
 // getRestClientConfig returns a K8s REST client config.
 func getRestClientConfig(ctx context.Context, model *K8sProviderModel) (*rest.Config, diag.Diagnostics) {
 	var diagnostics diag.Diagnostics
 
+	inCluster, diags := useInClusterConfig(model)
+	if diagnostics.Append(diags...); diagnostics.HasError() {
+		return nil, diagnostics
+	}
+
+	if inCluster {
+		return getInClusterRestClientConfig(ctx, model)
+	}
+
 	loader := &clientcmd.ClientConfigLoadingRules{}
 	overrides := &clientcmd.ConfigOverrides{}
 
+	if !model.ConfigRaw.IsNull() && !model.ConfigPaths.IsNull() {
+		diagnostics.AddAttributeError(path.Root("config_raw"), "Conflicting kube config.", "config_raw can't be set together with config_paths.")
+		return nil, diagnostics
+	}
+
+	var configRaw string
+	if !model.ConfigRaw.IsNull() {
+		configRaw = model.ConfigRaw.ValueString()
+	} else if v := os.Getenv("KUBE_CONFIG_RAW"); len(v) != 0 && model.ConfigPaths.IsNull() {
+		configRaw = v
+	}
+
 	var configPaths []string
-	if !model.ConfigPaths.IsNull() {
-		configPaths = make([]string, 0, len(model.ConfigPaths.Elements()))
-		diags := model.ConfigPaths.ElementsAs(ctx, &configPaths, false)
-		diagnostics.Append(diags...)
-		if diagnostics.HasError() {
-			return nil, diagnostics
+	if len(configRaw) == 0 {
+		if !model.ConfigPaths.IsNull() {
+			configPaths = make([]string, 0, len(model.ConfigPaths.Elements()))
+			diags := model.ConfigPaths.ElementsAs(ctx, &configPaths, false)
+			diagnostics.Append(diags...)
+			if diagnostics.HasError() {
+				return nil, diagnostics
+			}
+		} else if v := os.Getenv("KUBE_CONFIG_PATHS"); len(v) != 0 {
+			configPaths = filepath.SplitList(v)
+		} else if v := os.Getenv("KUBE_CONFIG_PATH"); len(v) != 0 {
+			configPaths = []string{v}
 		}
-	} else if v := os.Getenv("KUBE_CONFIG_PATHS"); len(v) != 0 {
-		configPaths = filepath.SplitList(v)
-	} else if v := os.Getenv("KUBE_CONFIG_PATH"); len(v) != 0 {
-		configPaths = []string{v}
 	}
 
 	if len(configPaths) > 0 {
@@ -54,7 +88,9 @@ func getRestClientConfig(ctx context.Context, model *K8sProviderModel) (*rest.Co
 		} else {
 			loader.Precedence = configPaths
 		}
+	}
 
+	if len(configPaths) > 0 || len(configRaw) > 0 {
 		if !model.ConfigContext.IsNull() || !model.ConfigContextAuthInfo.IsNull() || !model.ConfigContextCluster.IsNull() {
 			if !model.ConfigContext.IsNull() {
 				overrides.CurrentContext = model.ConfigContext.ValueString()
@@ -152,44 +188,13 @@ func getRestClientConfig(ctx context.Context, model *K8sProviderModel) (*rest.Co
 		overrides.AuthInfo.Token = v
 	}
 
-	if model.Exec != nil {
-		execModel := model.Exec
-		if !execModel.APIVersion.IsNull() && !execModel.Command.IsNull() {
-			args := []string{}
-			if !execModel.Args.IsNull() && !execModel.Args.IsUnknown() {
-				args = make([]string, 0, len(execModel.Args.Elements()))
-				diags := execModel.Args.ElementsAs(ctx, &args, false)
-				diagnostics.Append(diags...)
-				if diagnostics.HasError() {
-					return nil, diagnostics
-				}
-			}
-
-			env := []clientcmdapi.ExecEnvVar{}
-			if !execModel.Env.IsNull() && !execModel.Env.IsUnknown() {
-				env = make([]clientcmdapi.ExecEnvVar, 0, len(execModel.Env.Elements()))
-				for k, v := range execModel.Env.Elements() {
-					val, ok := v.(types.String)
-					if !ok {
-						diagnostics.AddError("Invalid type in exec env.", fmt.Sprintf("expected string, got %T", v))
-						return nil, diagnostics
-					}
-
-					env = append(env, clientcmdapi.ExecEnvVar{
-						Name:  k,
-						Value: val.ValueString(),
-					})
-				}
-			}
-
-			overrides.AuthInfo.Exec = &clientcmdapi.ExecConfig{
-				APIVersion:      execModel.APIVersion.ValueString(),
-				Command:         execModel.Command.ValueString(),
-				Args:            args,
-				Env:             env,
-				InteractiveMode: clientcmdapi.IfAvailableExecInteractiveMode,
-			}
+	if model.Exec != nil && !model.Exec.APIVersion.IsNull() && !model.Exec.Command.IsNull() {
+		execConfig, diags := getExecConfig(ctx, model.Exec)
+		if diagnostics.Append(diags...); diagnostics.HasError() {
+			return nil, diagnostics
 		}
+
+		overrides.AuthInfo.Exec = execConfig
 	}
 
 	if !model.ProxyURL.IsNull() {
@@ -198,12 +203,474 @@ func getRestClientConfig(ctx context.Context, model *K8sProviderModel) (*rest.Co
 		overrides.ClusterDefaults.ProxyURL = v
 	}
 
-	cc := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
+	var cc clientcmd.ClientConfig
+	if len(configRaw) > 0 {
+		rawConfig, err := clientcmd.Load([]byte(configRaw))
+		if err != nil {
+			diagnostics.AddError("Failed to parse raw kube config.", err.Error())
+			return nil, diagnostics
+		}
+
+		cc = clientcmd.NewNonInteractiveClientConfig(*rawConfig, overrides.CurrentContext, overrides, nil)
+	} else {
+		cc = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
+	}
+
 	config, err := cc.ClientConfig()
 	if err != nil {
 		diagnostics.AddError("Failed to load client config.", err.Error())
 		return nil, diagnostics
 	}
 
+	if diagnostics.Append(setRateLimits(model, config)...); diagnostics.HasError() {
+		return nil, diagnostics
+	}
+
+	if diagnostics.Append(setImpersonation(ctx, model, config)...); diagnostics.HasError() {
+		return nil, diagnostics
+	}
+
+	if diagnostics.Append(setOIDCAuth(model, config)...); diagnostics.HasError() {
+		return nil, diagnostics
+	}
+
+	return config, diagnostics
+}
+
+// setOIDCAuth sets the transport authenticating requests with an OIDC ID token in the REST client config; the token
+// source is shared by all the clients so the token is only refreshed once. OIDC can't be combined with any other
+// credentials, including those of the kube config user, as client-go would authenticate the requests with them instead.
+func setOIDCAuth(model *K8sProviderModel, config *rest.Config) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	if model.OIDC == nil {
+		return diagnostics
+	}
+
+	var credentials []string
+	for _, c := range []struct {
+		name string
+		set  bool
+	}{
+		{name: "token", set: len(config.BearerToken) > 0 || len(config.BearerTokenFile) > 0},
+		{name: "username and password", set: len(config.Username) > 0 || len(config.Password) > 0},
+		{name: "client certificate", set: len(config.CertData) > 0 || len(config.CertFile) > 0 || len(config.KeyData) > 0 || len(config.KeyFile) > 0},
+		{name: "exec", set: config.ExecProvider != nil},
+		{name: "auth provider", set: config.AuthProvider != nil},
+	} {
+		if c.set {
+			credentials = append(credentials, c.name)
+		}
+	}
+
+	if len(credentials) > 0 {
+		diagnostics.AddAttributeError(path.Root("oidc"), "Conflicting OIDC config.", fmt.Sprintf("oidc can't be used together with other credentials, including those of the kube config user; got %s", strings.Join(credentials, ", ")))
+		return diagnostics
+	}
+
+	source, err := k8sutils.NewOIDCTokenSource(k8sutils.OIDCOptions{
+		IssuerURL:    model.OIDC.IssuerURL.ValueString(),
+		ClientID:     model.OIDC.ClientID.ValueString(),
+		ClientSecret: model.OIDC.ClientSecret.ValueString(),
+		RefreshToken: model.OIDC.RefreshToken.ValueString(),
+		IDToken:      model.OIDC.IDToken.ValueString(),
+		CAData:       []byte(model.OIDC.CACertificate.ValueString()),
+		ExpiryDelta:  k8sutils.DefaultOIDCExpiryDelta,
+		Proxy:        config.Proxy,
+	})
+	if err != nil {
+		diagnostics.AddAttributeError(path.Root("oidc"), "Invalid OIDC config.", err.Error())
+		return diagnostics
+	}
+
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return k8sutils.NewOIDCRoundTripper(rt, source)
+	})
+
+	return diagnostics
+}
+
+// getExecConfig returns the exec plugin config; the env vars are sorted by name so the config is stable, as the exec
+// authenticator and the credential it returns are cached by config and shared by all the clients.
+func getExecConfig(ctx context.Context, execModel *ExecConfigModel) (*clientcmdapi.ExecConfig, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	args := []string{}
+	if !execModel.Args.IsNull() && !execModel.Args.IsUnknown() {
+		args = make([]string, 0, len(execModel.Args.Elements()))
+		if diagnostics.Append(execModel.Args.ElementsAs(ctx, &args, false)...); diagnostics.HasError() {
+			return nil, diagnostics
+		}
+	}
+
+	env := []clientcmdapi.ExecEnvVar{}
+	if !execModel.Env.IsNull() && !execModel.Env.IsUnknown() {
+		env = make([]clientcmdapi.ExecEnvVar, 0, len(execModel.Env.Elements()))
+		for k, v := range execModel.Env.Elements() {
+			val, ok := v.(types.String)
+			if !ok {
+				diagnostics.AddError("Invalid type in exec env.", fmt.Sprintf("expected string, got %T", v))
+				return nil, diagnostics
+			}
+
+			env = append(env, clientcmdapi.ExecEnvVar{
+				Name:  k,
+				Value: val.ValueString(),
+			})
+		}
+
+		slices.SortFunc(env, func(a, b clientcmdapi.ExecEnvVar) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+	}
+
+	interactiveMode := clientcmdapi.IfAvailableExecInteractiveMode
+	if !execModel.InteractiveMode.IsNull() {
+		interactiveMode = clientcmdapi.ExecInteractiveMode(execModel.InteractiveMode.ValueString())
+		switch interactiveMode {
+		case clientcmdapi.NeverExecInteractiveMode, clientcmdapi.IfAvailableExecInteractiveMode, clientcmdapi.AlwaysExecInteractiveMode:
+		default:
+			diagnostics.AddAttributeError(path.Root("exec").AtName("interactive_mode"), "Invalid exec interactive mode.", fmt.Sprintf("expected one of %q, %q or %q, got %q", clientcmdapi.NeverExecInteractiveMode, clientcmdapi.IfAvailableExecInteractiveMode, clientcmdapi.AlwaysExecInteractiveMode, interactiveMode))
+			return nil, diagnostics
+		}
+	}
+
+	return &clientcmdapi.ExecConfig{
+		APIVersion:         execModel.APIVersion.ValueString(),
+		Command:            execModel.Command.ValueString(),
+		Args:               args,
+		Env:                env,
+		InstallHint:        execModel.InstallHint.ValueString(),
+		ProvideClusterInfo: execModel.ProvideClusterInfo.ValueBool(),
+		InteractiveMode:    interactiveMode,
+	}, diagnostics
+}
+
+// useInClusterConfig returns true if the in-cluster config should be used; if this isn't configured the in-cluster
+// config is used when running in a pod and none of the attributes conflicting with it are configured.
+func useInClusterConfig(model *K8sProviderModel) (bool, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	if !model.InCluster.IsNull() {
+		return model.InCluster.ValueBool(), diagnostics
+	}
+
+	if v := os.Getenv("KUBE_IN_CLUSTER"); len(v) != 0 {
+		inCluster, err := strconv.ParseBool(v)
+		if err != nil {
+			diagnostics.AddError("Failed to parse KUBE_IN_CLUSTER environment variable.", err.Error())
+			return false, diagnostics
+		}
+
+		return inCluster, diagnostics
+	}
+
+	if len(os.Getenv("KUBERNETES_SERVICE_HOST")) == 0 {
+		return false, diagnostics
+	}
+
+	for _, c := range inClusterConflicts(model) {
+		if c.set || len(c.env()) > 0 {
+			return false, diagnostics
+		}
+	}
+
+	return true, diagnostics
+}
+
+// inClusterConflict is an attribute which can't be used together with the in-cluster config.
+type inClusterConflict struct {
+	name string
+	set  bool
+	envs []string
+}
+
+// env returns the name of the first environment variable of the attribute which is set.
+func (c inClusterConflict) env() string {
+	for _, k := range c.envs {
+		if len(os.Getenv(k)) != 0 {
+			return k
+		}
+	}
+
+	return ""
+}
+
+// inClusterConflicts returns the attributes which can't be used together with the in-cluster config.
+func inClusterConflicts(model *K8sProviderModel) []inClusterConflict {
+	return []inClusterConflict{
+		{name: "host", set: !model.Host.IsNull(), envs: []string{"KUBE_HOST"}},
+		{name: "insecure", set: !model.Insecure.IsNull(), envs: []string{"KUBE_INSECURE"}},
+		{name: "tls_server_name", set: !model.TLSServerName.IsNull(), envs: []string{"KUBE_TLS_SERVER_NAME"}},
+		{name: "client_certificate", set: !model.ClientCertificate.IsNull(), envs: []string{"KUBE_CLIENT_CERT_DATA"}},
+		{name: "client_key", set: !model.ClientKey.IsNull(), envs: []string{"KUBE_CLIENT_KEY_DATA"}},
+		{name: "cluster_ca_certificate", set: !model.ClusterCACertificate.IsNull(), envs: []string{"KUBE_CLUSTER_CA_CERT_DATA"}},
+		{name: "config_paths", set: !model.ConfigPaths.IsNull(), envs: []string{"KUBE_CONFIG_PATHS", "KUBE_CONFIG_PATH"}},
+		{name: "config_raw", set: !model.ConfigRaw.IsNull(), envs: []string{"KUBE_CONFIG_RAW"}},
+		{name: "config_context", set: !model.ConfigContext.IsNull(), envs: []string{"KUBE_CTX"}},
+		{name: "config_context_auth_info", set: !model.ConfigContextAuthInfo.IsNull(), envs: []string{"KUBE_CTX_AUTH_INFO"}},
+		{name: "config_context_cluster", set: !model.ConfigContextCluster.IsNull(), envs: []string{"KUBE_CTX_CLUSTER"}},
+		{name: "username", set: !model.Username.IsNull(), envs: []string{"KUBE_USER"}},
+		{name: "password", set: !model.Password.IsNull(), envs: []string{"KUBE_PASSWORD"}},
+		{name: "token", set: !model.Token.IsNull(), envs: []string{"KUBE_TOKEN"}},
+		{name: "proxy_url", set: !model.ProxyURL.IsNull(), envs: []string{"KUBE_PROXY_URL"}},
+		{name: "exec", set: model.Exec != nil},
+		{name: "oidc", set: model.OIDC != nil},
+	}
+}
+
+// getInClusterRestClientConfig returns a K8s REST client config using the service account mounted into the pod; the
+// token is read from its file so rotated tokens are picked up.
+func getInClusterRestClientConfig(ctx context.Context, model *K8sProviderModel) (*rest.Config, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	for _, c := range inClusterConflicts(model) {
+		if c.set {
+			diagnostics.AddAttributeError(path.Root(c.name), "Conflicting in-cluster config.", fmt.Sprintf("%s can't be set when the in-cluster config is used", c.name))
+		} else if env := c.env(); len(env) > 0 {
+			diagnostics.AddError("Conflicting in-cluster config.", fmt.Sprintf("the %s environment variable can't be set when the in-cluster config is used", env))
+		}
+	}
+
+	if diagnostics.HasError() {
+		return nil, diagnostics
+	}
+
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		diagnostics.AddError("Failed to load in-cluster config.", err.Error())
+		return nil, diagnostics
+	}
+
+	if diagnostics.Append(setRateLimits(model, config)...); diagnostics.HasError() {
+		return nil, diagnostics
+	}
+
+	if diagnostics.Append(setImpersonation(ctx, model, config)...); diagnostics.HasError() {
+		return nil, diagnostics
+	}
+
 	return config, diagnostics
 }
+
+// setImpersonation sets the user and groups to impersonate in the REST client config.
+func setImpersonation(ctx context.Context, model *K8sProviderModel, config *rest.Config) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	impersonateModel := model.Impersonate
+	if impersonateModel == nil {
+		impersonateModel = &ImpersonateModel{}
+	}
+
+	if !impersonateModel.User.IsNull() {
+		config.Impersonate.UserName = impersonateModel.User.ValueString()
+	} else if v := os.Getenv("KUBE_IMPERSONATE_USER"); len(v) != 0 {
+		config.Impersonate.UserName = v
+	}
+
+	if !impersonateModel.UID.IsNull() {
+		config.Impersonate.UID = impersonateModel.UID.ValueString()
+	} else if v := os.Getenv("KUBE_IMPERSONATE_UID"); len(v) != 0 {
+		config.Impersonate.UID = v
+	}
+
+	if !impersonateModel.Groups.IsNull() {
+		groups := make([]string, 0, len(impersonateModel.Groups.Elements()))
+		if diagnostics.Append(impersonateModel.Groups.ElementsAs(ctx, &groups, false)...); diagnostics.HasError() {
+			return diagnostics
+		}
+
+		config.Impersonate.Groups = groups
+	} else if v := os.Getenv("KUBE_IMPERSONATE_GROUPS"); len(v) != 0 {
+		config.Impersonate.Groups = strings.Split(v, ",")
+	}
+
+	if !impersonateModel.Extra.IsNull() {
+		extra := make(map[string][]string, len(impersonateModel.Extra.Elements()))
+		if diagnostics.Append(impersonateModel.Extra.ElementsAs(ctx, &extra, false)...); diagnostics.HasError() {
+			return diagnostics
+		}
+
+		config.Impersonate.Extra = extra
+	} else if v := os.Getenv("KUBE_IMPERSONATE_EXTRA"); len(v) != 0 {
+		extra := map[string][]string{}
+		for pair := range strings.SplitSeq(v, ",") {
+			k, val, ok := strings.Cut(pair, "=")
+			if !ok {
+				diagnostics.AddError("Failed to parse KUBE_IMPERSONATE_EXTRA environment variable.", fmt.Sprintf("expected key=value, got %q", pair))
+				return diagnostics
+			}
+
+			extra[k] = append(extra[k], val)
+		}
+
+		config.Impersonate.Extra = extra
+	}
+
+	if len(config.Impersonate.UserName) == 0 && (len(config.Impersonate.UID) != 0 || len(config.Impersonate.Groups) != 0 || len(config.Impersonate.Extra) != 0) {
+		diagnostics.AddAttributeError(path.Root("impersonate").AtName("user"), "Invalid impersonation.", "user is required to impersonate a UID, groups or extra fields")
+		return diagnostics
+	}
+
+	return diagnostics
+}
+
+// setRateLimits sets the client side throttling and the retry transport of the REST client config.
+func setRateLimits(model *K8sProviderModel, config *rest.Config) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	if !model.QPS.IsNull() {
+		config.QPS = float32(model.QPS.ValueFloat64())
+	} else if v := os.Getenv("KUBE_QPS"); len(v) != 0 {
+		qps, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			diagnostics.AddError("Failed to parse KUBE_QPS environment variable.", err.Error())
+			return diagnostics
+		}
+
+		config.QPS = float32(qps)
+	}
+
+	if !model.Burst.IsNull() {
+		config.Burst = int(model.Burst.ValueInt64())
+	} else if v := os.Getenv("KUBE_BURST"); len(v) != 0 {
+		burst, err := strconv.Atoi(v)
+		if err != nil {
+			diagnostics.AddError("Failed to parse KUBE_BURST environment variable.", err.Error())
+			return diagnostics
+		}
+
+		config.Burst = burst
+	}
+
+	if config.QPS < 0 {
+		diagnostics.AddAttributeError(path.Root("qps"), "Invalid QPS.", "qps can't be negative")
+		return diagnostics
+	}
+
+	if config.Burst < 0 {
+		diagnostics.AddAttributeError(path.Root("burst"), "Invalid burst.", "burst can't be negative")
+		return diagnostics
+	}
+
+	if model.Retry == nil {
+		return diagnostics
+	}
+
+	opts := k8sutils.RetryOptions{
+		MaxAttempts: 3,
+		MinBackoff:  time.Second,
+		MaxBackoff:  30 * time.Second,
+	}
+
+	if !model.Retry.MaxAttempts.IsNull() {
+		opts.MaxAttempts = int(model.Retry.MaxAttempts.ValueInt64())
+		if opts.MaxAttempts < 1 {
+			diagnostics.AddAttributeError(path.Root("retry").AtName("max_attempts"), "Invalid retry max attempts.", "max_attempts must be at least 1")
+			return diagnostics
+		}
+	}
+
+	for _, b := range []struct {
+		name   string
+		value  types.String
+		target *time.Duration
+	}{
+		{name: "min_backoff", value: model.Retry.MinBackoff, target: &opts.MinBackoff},
+		{name: "max_backoff", value: model.Retry.MaxBackoff, target: &opts.MaxBackoff},
+	} {
+		if b.value.IsNull() {
+			continue
+		}
+
+		d, err := time.ParseDuration(b.value.ValueString())
+		if err != nil {
+			diagnostics.AddAttributeError(path.Root("retry").AtName(b.name), "Failed to parse retry backoff.", err.Error())
+			return diagnostics
+		}
+
+		*b.target = d
+	}
+
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return k8sutils.NewRetryRoundTripper(rt, opts)
+	})
+
+	return diagnostics
+}
+
+// getClientOptions returns the K8s provider client options.
+func getClientOptions(model *K8sProviderModel) ([]K8sProviderClientOption, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	var opts []K8sProviderClientOption
+
+	var openAPICacheDir string
+	if !model.OpenAPICacheDir.IsNull() {
+		openAPICacheDir = model.OpenAPICacheDir.ValueString()
+	} else if v := os.Getenv("KUBE_OPENAPI_CACHE_DIR"); len(v) != 0 {
+		openAPICacheDir = v
+	}
+
+	if len(openAPICacheDir) > 0 {
+		dir, err := homedir.Expand(openAPICacheDir)
+		if err != nil {
+			diagnostics.AddError("Failed to expand home directory.", err.Error())
+			return nil, diagnostics
+		}
+
+		opts = append(opts, WithOpenAPICacheDir(dir))
+	}
+
+	if model.DiscoveryCache != nil {
+		discoveryCacheDir := "~/.kube/cache"
+		if !model.DiscoveryCache.Dir.IsNull() {
+			discoveryCacheDir = model.DiscoveryCache.Dir.ValueString()
+		} else if v := os.Getenv("KUBE_DISCOVERY_CACHE_DIR"); len(v) != 0 {
+			discoveryCacheDir = v
+		}
+
+		dir, err := homedir.Expand(discoveryCacheDir)
+		if err != nil {
+			diagnostics.AddError("Failed to expand home directory.", err.Error())
+			return nil, diagnostics
+		}
+
+		ttl := 6 * time.Hour
+		if !model.DiscoveryCache.TTL.IsNull() {
+			ttl, err = time.ParseDuration(model.DiscoveryCache.TTL.ValueString())
+			if err != nil {
+				diagnostics.AddAttributeError(path.Root("discovery_cache").AtName("ttl"), "Failed to parse discovery cache TTL.", err.Error())
+				return nil, diagnostics
+			}
+		}
+
+		opts = append(opts, WithDiscoveryCache(dir, ttl))
+	}
+
+	inCluster, diags := useInClusterConfig(model)
+	if diagnostics.Append(diags...); diagnostics.HasError() {
+		return nil, diagnostics
+	}
+
+	if inCluster {
+		opts = append(opts, WithDefaultNamespace(getInClusterNamespace(serviceAccountNamespaceFile)))
+	}
+
+	return opts, diagnostics
+}
+
+// getInClusterNamespace returns the namespace of the service account from the given file; if the file can't be read
+// this returns the default namespace, in the same way as kubectl.
+func getInClusterNamespace(file string) string {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return metav1.NamespaceDefault
+	}
+
+	if ns := strings.TrimSpace(string(b)); len(ns) > 0 {
+		return ns
+	}
+
+	return metav1.NamespaceDefault
+}
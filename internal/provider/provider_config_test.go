@@ -1 +1,944 @@
 package provider
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/mitchellh/go-homedir"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachineryschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const testRawConfig = `apiVersion: v1
+kind: Config
+current-context: foo
+clusters:
+  - name: foo
+    cluster:
+      server: https://foo.example.com
+  - name: bar
+    cluster:
+      server: https://bar.example.com
+users:
+  - name: foo
+    user:
+      token: foo-token
+  - name: bar
+    user:
+      token: bar-token
+contexts:
+  - name: foo
+    context:
+      cluster: foo
+      user: foo
+  - name: bar
+    context:
+      cluster: bar
+      user: bar
+`
+
+func TestGetRestClientConfig(t *testing.T) {
+	for _, d := range []struct {
+		testName  string
+		model     *K8sProviderModel
+		env       map[string]string
+		wantHost  string
+		wantToken string
+		errMsg    string
+	}{
+		{
+			testName:  "config_raw",
+			model:     &K8sProviderModel{ConfigRaw: types.StringValue(testRawConfig)},
+			wantHost:  "https://foo.example.com",
+			wantToken: "foo-token",
+		},
+		{
+			testName:  "config_raw_env",
+			model:     &K8sProviderModel{},
+			env:       map[string]string{"KUBE_CONFIG_RAW": testRawConfig},
+			wantHost:  "https://foo.example.com",
+			wantToken: "foo-token",
+		},
+		{
+			testName:  "config_raw_context",
+			model:     &K8sProviderModel{ConfigRaw: types.StringValue(testRawConfig), ConfigContext: types.StringValue("bar")},
+			wantHost:  "https://bar.example.com",
+			wantToken: "bar-token",
+		},
+		{
+			testName:  "config_raw_context_overrides",
+			model:     &K8sProviderModel{ConfigRaw: types.StringValue(testRawConfig), ConfigContextCluster: types.StringValue("bar")},
+			wantHost:  "https://bar.example.com",
+			wantToken: "foo-token",
+		},
+		{
+			testName:  "config_raw_token",
+			model:     &K8sProviderModel{ConfigRaw: types.StringValue(testRawConfig), Token: types.StringValue("token")},
+			wantHost:  "https://foo.example.com",
+			wantToken: "token",
+		},
+		{
+			testName: "config_raw_invalid",
+			model:    &K8sProviderModel{ConfigRaw: types.StringValue("foo")},
+			errMsg:   "Failed to parse raw kube config.",
+		},
+		{
+			testName: "config_raw_and_config_paths",
+			model: &K8sProviderModel{
+				ConfigRaw:   types.StringValue(testRawConfig),
+				ConfigPaths: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("~/.kube/config")}),
+			},
+			errMsg: "Conflicting kube config.",
+		},
+		{
+			testName: "config_raw_oidc",
+			model: &K8sProviderModel{
+				ConfigRaw: types.StringValue(testRawConfig),
+				OIDC:      &OIDCModel{IssuerURL: types.StringValue("https://issuer.example.com"), ClientID: types.StringValue("client"), RefreshToken: types.StringValue("foo")},
+			},
+			errMsg: "Conflicting OIDC config.",
+		},
+		{
+			testName: "in_cluster_token",
+			model:    &K8sProviderModel{InCluster: types.BoolValue(true), Token: types.StringValue("token")},
+			errMsg:   "Conflicting in-cluster config.",
+		},
+		{
+			testName: "in_cluster_config_paths_and_exec",
+			model: &K8sProviderModel{
+				InCluster:   types.BoolValue(true),
+				ConfigPaths: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("~/.kube/config")}),
+				Exec:        &ExecConfigModel{Command: types.StringValue("foo")},
+			},
+			errMsg: "Conflicting in-cluster config.: Conflicting in-cluster config.",
+		},
+		{
+			testName: "in_cluster_oidc",
+			model: &K8sProviderModel{
+				InCluster: types.BoolValue(true),
+				OIDC:      &OIDCModel{IssuerURL: types.StringValue("https://issuer.example.com"), ClientID: types.StringValue("client"), RefreshToken: types.StringValue("foo")},
+			},
+			errMsg: "Conflicting in-cluster config.",
+		},
+		{
+			testName: "in_cluster_token_env",
+			model:    &K8sProviderModel{InCluster: types.BoolValue(true)},
+			env:      map[string]string{"KUBE_TOKEN": "token"},
+			errMsg:   "Conflicting in-cluster config.",
+		},
+		{
+			testName: "in_cluster_env_config_path_env",
+			model:    &K8sProviderModel{},
+			env:      map[string]string{"KUBE_IN_CLUSTER": "true", "KUBE_CONFIG_PATH": "~/.kube/config"},
+			errMsg:   "Conflicting in-cluster config.",
+		},
+		{
+			testName:  "in_cluster_fallback_skipped_for_token",
+			model:     &K8sProviderModel{Token: types.StringValue("token")},
+			env:       map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "KUBE_CONFIG_RAW": testRawConfig},
+			wantHost:  "https://foo.example.com",
+			wantToken: "token",
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			for _, k := range []string{"KUBE_IN_CLUSTER", "KUBERNETES_SERVICE_HOST", "KUBE_CONFIG_PATHS", "KUBE_CONFIG_PATH", "KUBE_CONFIG_RAW", "KUBE_CTX", "KUBE_CTX_AUTH_INFO", "KUBE_CTX_CLUSTER", "KUBE_HOST", "KUBE_INSECURE", "KUBE_TLS_SERVER_NAME", "KUBE_CLUSTER_CA_CERT_DATA", "KUBE_CLIENT_CERT_DATA", "KUBE_CLIENT_KEY_DATA", "KUBE_USER", "KUBE_PASSWORD", "KUBE_TOKEN", "KUBE_PROXY_URL"} {
+				t.Setenv(k, "")
+			}
+			for k, v := range d.env {
+				t.Setenv(k, v)
+			}
+
+			config, diags := getRestClientConfig(t.Context(), d.model)
+
+			var errMsg string
+			if diags.HasError() {
+				for i, diag := range diags.Errors() {
+					if i == 0 {
+						errMsg = diag.Summary()
+						continue
+					}
+					errMsg = fmt.Sprintf("%s: %s", errMsg, diag.Summary())
+				}
+			}
+
+			if errMsg != d.errMsg {
+				t.Errorf("getRestClientConfig returned error message %q, want %q", errMsg, d.errMsg)
+			}
+
+			if diags.HasError() {
+				return
+			}
+
+			if config.Host != d.wantHost {
+				t.Errorf("getRestClientConfig set host to %q, want %q", config.Host, d.wantHost)
+			}
+
+			if config.BearerToken != d.wantToken {
+				t.Errorf("getRestClientConfig set bearer token to %q, want %q", config.BearerToken, d.wantToken)
+			}
+		})
+	}
+}
+
+func TestGetClientOptions(t *testing.T) {
+	t.Setenv("KUBE_OPENAPI_CACHE_DIR", "")
+	t.Setenv("KUBE_DISCOVERY_CACHE_DIR", "")
+	t.Setenv("KUBE_IN_CLUSTER", "")
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	home, err := homedir.Dir()
+	if err != nil {
+		t.Fatalf("failed to get home directory: %v", err)
+	}
+
+	type clientOptions struct {
+		DefaultNamespace  string
+		OpenAPICacheDir   string
+		DiscoveryCacheDir string
+		DiscoveryCacheTTL time.Duration
+	}
+
+	for _, d := range []struct {
+		testName string
+		model    *K8sProviderModel
+		want     clientOptions
+		errMsg   string
+	}{
+		{
+			testName: "defaults",
+			model:    &K8sProviderModel{},
+			want:     clientOptions{},
+		},
+		{
+			testName: "openapi_cache_dir",
+			model:    &K8sProviderModel{OpenAPICacheDir: types.StringValue("~/openapi")},
+			want:     clientOptions{OpenAPICacheDir: filepath.Join(home, "openapi")},
+		},
+		{
+			testName: "discovery_cache_defaults",
+			model:    &K8sProviderModel{DiscoveryCache: &DiscoveryCacheModel{}},
+			want: clientOptions{
+				DiscoveryCacheDir: filepath.Join(home, ".kube", "cache"),
+				DiscoveryCacheTTL: 6 * time.Hour,
+			},
+		},
+		{
+			testName: "discovery_cache",
+			model: &K8sProviderModel{DiscoveryCache: &DiscoveryCacheModel{
+				Dir: types.StringValue("/tmp/cache"),
+				TTL: types.StringValue("10m"),
+			}},
+			want: clientOptions{
+				DiscoveryCacheDir: "/tmp/cache",
+				DiscoveryCacheTTL: 10 * time.Minute,
+			},
+		},
+		{
+			testName: "discovery_cache_invalid_ttl",
+			model: &K8sProviderModel{DiscoveryCache: &DiscoveryCacheModel{
+				TTL: types.StringValue("foo"),
+			}},
+			errMsg: "Failed to parse discovery cache TTL.",
+		},
+		{
+			testName: "in_cluster",
+			model:    &K8sProviderModel{InCluster: types.BoolValue(true)},
+			want:     clientOptions{DefaultNamespace: getInClusterNamespace(serviceAccountNamespaceFile)},
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			opts, diags := getClientOptions(d.model)
+
+			var errMsg string
+			if diags.HasError() {
+				for i, diag := range diags.Errors() {
+					if i == 0 {
+						errMsg = diag.Summary()
+						continue
+					}
+					errMsg = fmt.Sprintf("%s: %s", errMsg, diag.Summary())
+				}
+			}
+
+			if errMsg != d.errMsg {
+				t.Errorf("getClientOptions returned error message %q, want %q", errMsg, d.errMsg)
+			}
+
+			if diags.HasError() {
+				return
+			}
+
+			client := NewK8sProviderClient(nil, opts...)
+			got := clientOptions{
+				DefaultNamespace:  client.defaultNamespace,
+				OpenAPICacheDir:   client.openAPICacheDir,
+				DiscoveryCacheDir: client.discoveryCacheDir,
+				DiscoveryCacheTTL: client.discoveryCacheTTL,
+			}
+
+			if diff := cmp.Diff(d.want, got); diff != "" {
+				t.Errorf("getClientOptions() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetInClusterNamespace(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	for _, d := range []struct {
+		testName string
+		content  *string
+		want     string
+	}{
+		{
+			testName: "namespace",
+			content:  new("foo\n"),
+			want:     "foo",
+		},
+		{
+			testName: "empty",
+			content:  new(""),
+			want:     "default",
+		},
+		{
+			testName: "missing",
+			want:     "default",
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			file := filepath.Join(dir, d.testName)
+			if d.content != nil {
+				if err := os.WriteFile(file, []byte(*d.content), 0o600); err != nil {
+					t.Fatalf("failed to write namespace file: %v", err)
+				}
+			}
+
+			if got := getInClusterNamespace(file); got != d.want {
+				t.Errorf("getInClusterNamespace returned %q, want %q", got, d.want)
+			}
+		})
+	}
+}
+
+func TestSetRateLimits(t *testing.T) {
+	for _, d := range []struct {
+		testName  string
+		model     *K8sProviderModel
+		env       map[string]string
+		wantQPS   float32
+		wantBurst int
+		wantRetry bool
+		errMsg    string
+	}{
+		{
+			testName: "defaults",
+			model:    &K8sProviderModel{},
+		},
+		{
+			testName:  "qps_and_burst",
+			model:     &K8sProviderModel{QPS: types.Float64Value(50), Burst: types.Int64Value(100)},
+			wantQPS:   50,
+			wantBurst: 100,
+		},
+		{
+			testName:  "qps_and_burst_env",
+			model:     &K8sProviderModel{},
+			env:       map[string]string{"KUBE_QPS": "20.5", "KUBE_BURST": "40"},
+			wantQPS:   20.5,
+			wantBurst: 40,
+		},
+		{
+			testName: "model_overrides_env",
+			model:    &K8sProviderModel{QPS: types.Float64Value(50)},
+			env:      map[string]string{"KUBE_QPS": "20"},
+			wantQPS:  50,
+		},
+		{
+			testName: "invalid_qps_env",
+			model:    &K8sProviderModel{},
+			env:      map[string]string{"KUBE_QPS": "foo"},
+			errMsg:   "Failed to parse KUBE_QPS environment variable.",
+		},
+		{
+			testName: "negative_burst",
+			model:    &K8sProviderModel{Burst: types.Int64Value(-1)},
+			errMsg:   "Invalid burst.",
+		},
+		{
+			testName:  "retry",
+			model:     &K8sProviderModel{Retry: &RetryModel{}},
+			wantRetry: true,
+		},
+		{
+			testName: "retry_invalid_max_attempts",
+			model:    &K8sProviderModel{Retry: &RetryModel{MaxAttempts: types.Int64Value(0)}},
+			errMsg:   "Invalid retry max attempts.",
+		},
+		{
+			testName: "retry_invalid_backoff",
+			model:    &K8sProviderModel{Retry: &RetryModel{MaxBackoff: types.StringValue("foo")}},
+			errMsg:   "Failed to parse retry backoff.",
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Setenv("KUBE_QPS", "")
+			t.Setenv("KUBE_BURST", "")
+			for k, v := range d.env {
+				t.Setenv(k, v)
+			}
+
+			config := &rest.Config{}
+			diags := setRateLimits(d.model, config)
+
+			var errMsg string
+			if diags.HasError() {
+				for i, diag := range diags.Errors() {
+					if i == 0 {
+						errMsg = diag.Summary()
+						continue
+					}
+					errMsg = fmt.Sprintf("%s: %s", errMsg, diag.Summary())
+				}
+			}
+
+			if errMsg != d.errMsg {
+				t.Errorf("setRateLimits returned error message %q, want %q", errMsg, d.errMsg)
+			}
+
+			if diags.HasError() {
+				return
+			}
+
+			if config.QPS != d.wantQPS {
+				t.Errorf("setRateLimits set QPS to %v, want %v", config.QPS, d.wantQPS)
+			}
+
+			if config.Burst != d.wantBurst {
+				t.Errorf("setRateLimits set burst to %d, want %d", config.Burst, d.wantBurst)
+			}
+
+			if gotRetry := config.WrapTransport != nil; gotRetry != d.wantRetry {
+				t.Errorf("setRateLimits set retry transport %t, want %t", gotRetry, d.wantRetry)
+			}
+		})
+	}
+}
+
+func TestUseInClusterConfig(t *testing.T) {
+	for _, d := range []struct {
+		testName string
+		model    *K8sProviderModel
+		env      map[string]string
+		want     bool
+		errMsg   string
+	}{
+		{
+			testName: "defaults",
+			model:    &K8sProviderModel{},
+			want:     false,
+		},
+		{
+			testName: "in_cluster",
+			model:    &K8sProviderModel{InCluster: types.BoolValue(true)},
+			want:     true,
+		},
+		{
+			testName: "in_cluster_disabled",
+			model:    &K8sProviderModel{InCluster: types.BoolValue(false)},
+			env:      map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"},
+			want:     false,
+		},
+		{
+			testName: "in_cluster_env",
+			model:    &K8sProviderModel{},
+			env:      map[string]string{"KUBE_IN_CLUSTER": "true"},
+			want:     true,
+		},
+		{
+			testName: "invalid_in_cluster_env",
+			model:    &K8sProviderModel{},
+			env:      map[string]string{"KUBE_IN_CLUSTER": "foo"},
+			errMsg:   "Failed to parse KUBE_IN_CLUSTER environment variable.",
+		},
+		{
+			testName: "fallback",
+			model:    &K8sProviderModel{},
+			env:      map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"},
+			want:     true,
+		},
+		{
+			testName: "fallback_config_paths",
+			model:    &K8sProviderModel{ConfigPaths: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("~/.kube/config")})},
+			env:      map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"},
+			want:     false,
+		},
+		{
+			testName: "fallback_config_path_env",
+			model:    &K8sProviderModel{},
+			env:      map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "KUBE_CONFIG_PATH": "~/.kube/config"},
+			want:     false,
+		},
+		{
+			testName: "fallback_config_raw",
+			model:    &K8sProviderModel{ConfigRaw: types.StringValue(testRawConfig)},
+			env:      map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"},
+			want:     false,
+		},
+		{
+			testName: "fallback_host",
+			model:    &K8sProviderModel{Host: types.StringValue("https://example.com")},
+			env:      map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"},
+			want:     false,
+		},
+		{
+			testName: "fallback_host_env",
+			model:    &K8sProviderModel{},
+			env:      map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "KUBE_HOST": "https://example.com"},
+			want:     false,
+		},
+		{
+			testName: "fallback_token",
+			model:    &K8sProviderModel{Token: types.StringValue("token")},
+			env:      map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"},
+			want:     false,
+		},
+		{
+			testName: "fallback_token_env",
+			model:    &K8sProviderModel{},
+			env:      map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "KUBE_TOKEN": "token"},
+			want:     false,
+		},
+		{
+			testName: "fallback_context_env",
+			model:    &K8sProviderModel{},
+			env:      map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "KUBE_CTX": "foo"},
+			want:     false,
+		},
+		{
+			testName: "fallback_exec",
+			model:    &K8sProviderModel{Exec: &ExecConfigModel{Command: types.StringValue("foo")}},
+			env:      map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"},
+			want:     false,
+		},
+		{
+			testName: "fallback_oidc",
+			model:    &K8sProviderModel{OIDC: &OIDCModel{IssuerURL: types.StringValue("https://issuer.example.com")}},
+			env:      map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"},
+			want:     false,
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			for _, k := range []string{"KUBE_IN_CLUSTER", "KUBERNETES_SERVICE_HOST", "KUBE_CONFIG_PATHS", "KUBE_CONFIG_PATH", "KUBE_CONFIG_RAW", "KUBE_CTX", "KUBE_CTX_AUTH_INFO", "KUBE_CTX_CLUSTER", "KUBE_HOST", "KUBE_INSECURE", "KUBE_TLS_SERVER_NAME", "KUBE_CLUSTER_CA_CERT_DATA", "KUBE_CLIENT_CERT_DATA", "KUBE_CLIENT_KEY_DATA", "KUBE_USER", "KUBE_PASSWORD", "KUBE_TOKEN", "KUBE_PROXY_URL"} {
+				t.Setenv(k, "")
+			}
+			for k, v := range d.env {
+				t.Setenv(k, v)
+			}
+
+			got, diags := useInClusterConfig(d.model)
+
+			var errMsg string
+			if diags.HasError() {
+				for i, diag := range diags.Errors() {
+					if i == 0 {
+						errMsg = diag.Summary()
+						continue
+					}
+					errMsg = fmt.Sprintf("%s: %s", errMsg, diag.Summary())
+				}
+			}
+
+			if errMsg != d.errMsg {
+				t.Errorf("useInClusterConfig returned error message %q, want %q", errMsg, d.errMsg)
+			}
+
+			if got != d.want {
+				t.Errorf("useInClusterConfig returned %t, want %t", got, d.want)
+			}
+		})
+	}
+}
+
+func TestSetImpersonation(t *testing.T) {
+	for _, d := range []struct {
+		testName string
+		model    *K8sProviderModel
+		env      map[string]string
+		want     rest.ImpersonationConfig
+		errMsg   string
+	}{
+		{
+			testName: "defaults",
+			model:    &K8sProviderModel{},
+		},
+		{
+			testName: "impersonate",
+			model: &K8sProviderModel{Impersonate: &ImpersonateModel{
+				User:   types.StringValue("foo"),
+				UID:    types.StringValue("1234"),
+				Groups: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("bar"), types.StringValue("baz")}),
+				Extra: types.MapValueMust(types.ListType{ElemType: types.StringType}, map[string]attr.Value{
+					"scopes": types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a"), types.StringValue("b")}),
+				}),
+			}},
+			want: rest.ImpersonationConfig{
+				UserName: "foo",
+				UID:      "1234",
+				Groups:   []string{"bar", "baz"},
+				Extra:    map[string][]string{"scopes": {"a", "b"}},
+			},
+		},
+		{
+			testName: "impersonate_env",
+			model:    &K8sProviderModel{},
+			env: map[string]string{
+				"KUBE_IMPERSONATE_USER":   "foo",
+				"KUBE_IMPERSONATE_UID":    "1234",
+				"KUBE_IMPERSONATE_GROUPS": "bar,baz",
+				"KUBE_IMPERSONATE_EXTRA":  "scopes=a,scopes=b,team=c",
+			},
+			want: rest.ImpersonationConfig{
+				UserName: "foo",
+				UID:      "1234",
+				Groups:   []string{"bar", "baz"},
+				Extra:    map[string][]string{"scopes": {"a", "b"}, "team": {"c"}},
+			},
+		},
+		{
+			testName: "model_overrides_env",
+			model:    &K8sProviderModel{Impersonate: &ImpersonateModel{User: types.StringValue("foo")}},
+			env:      map[string]string{"KUBE_IMPERSONATE_USER": "bar"},
+			want:     rest.ImpersonationConfig{UserName: "foo"},
+		},
+		{
+			testName: "invalid_extra_env",
+			model:    &K8sProviderModel{},
+			env:      map[string]string{"KUBE_IMPERSONATE_USER": "foo", "KUBE_IMPERSONATE_EXTRA": "scopes"},
+			errMsg:   "Failed to parse KUBE_IMPERSONATE_EXTRA environment variable.",
+		},
+		{
+			testName: "groups_without_user",
+			model: &K8sProviderModel{Impersonate: &ImpersonateModel{
+				Groups: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("bar")}),
+			}},
+			errMsg: "Invalid impersonation.",
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			for _, k := range []string{"KUBE_IMPERSONATE_USER", "KUBE_IMPERSONATE_UID", "KUBE_IMPERSONATE_GROUPS", "KUBE_IMPERSONATE_EXTRA"} {
+				t.Setenv(k, "")
+			}
+			for k, v := range d.env {
+				t.Setenv(k, v)
+			}
+
+			config := &rest.Config{}
+			diags := setImpersonation(t.Context(), d.model, config)
+
+			var errMsg string
+			if diags.HasError() {
+				for i, diag := range diags.Errors() {
+					if i == 0 {
+						errMsg = diag.Summary()
+						continue
+					}
+					errMsg = fmt.Sprintf("%s: %s", errMsg, diag.Summary())
+				}
+			}
+
+			if errMsg != d.errMsg {
+				t.Errorf("setImpersonation returned error message %q, want %q", errMsg, d.errMsg)
+			}
+
+			if diags.HasError() {
+				return
+			}
+
+			if diff := cmp.Diff(d.want, config.Impersonate); diff != "" {
+				t.Errorf("setImpersonation() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetExecConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []struct {
+		testName  string
+		execModel *ExecConfigModel
+		want      *clientcmdapi.ExecConfig
+		errMsg    string
+	}{
+		{
+			testName: "defaults",
+			execModel: &ExecConfigModel{
+				APIVersion: types.StringValue("client.authentication.k8s.io/v1"),
+				Command:    types.StringValue("foo"),
+			},
+			want: &clientcmdapi.ExecConfig{
+				APIVersion:      "client.authentication.k8s.io/v1",
+				Command:         "foo",
+				Args:            []string{},
+				Env:             []clientcmdapi.ExecEnvVar{},
+				InteractiveMode: clientcmdapi.IfAvailableExecInteractiveMode,
+			},
+		},
+		{
+			testName: "all",
+			execModel: &ExecConfigModel{
+				APIVersion: types.StringValue("client.authentication.k8s.io/v1"),
+				Command:    types.StringValue("foo"),
+				Args:       types.ListValueMust(types.StringType, []attr.Value{types.StringValue("bar")}),
+				Env: types.MapValueMust(types.StringType, map[string]attr.Value{
+					"C": types.StringValue("c"),
+					"A": types.StringValue("a"),
+					"B": types.StringValue("b"),
+				}),
+				InteractiveMode:    types.StringValue("Never"),
+				ProvideClusterInfo: types.BoolValue(true),
+				InstallHint:        types.StringValue("Install foo."),
+			},
+			want: &clientcmdapi.ExecConfig{
+				APIVersion: "client.authentication.k8s.io/v1",
+				Command:    "foo",
+				Args:       []string{"bar"},
+				Env: []clientcmdapi.ExecEnvVar{
+					{Name: "A", Value: "a"},
+					{Name: "B", Value: "b"},
+					{Name: "C", Value: "c"},
+				},
+				InstallHint:        "Install foo.",
+				ProvideClusterInfo: true,
+				InteractiveMode:    clientcmdapi.NeverExecInteractiveMode,
+			},
+		},
+		{
+			testName: "invalid_interactive_mode",
+			execModel: &ExecConfigModel{
+				APIVersion:      types.StringValue("client.authentication.k8s.io/v1"),
+				Command:         types.StringValue("foo"),
+				InteractiveMode: types.StringValue("Sometimes"),
+			},
+			errMsg: "Invalid exec interactive mode.",
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got, diags := getExecConfig(t.Context(), d.execModel)
+
+			var errMsg string
+			if diags.HasError() {
+				for i, diag := range diags.Errors() {
+					if i == 0 {
+						errMsg = diag.Summary()
+						continue
+					}
+					errMsg = fmt.Sprintf("%s: %s", errMsg, diag.Summary())
+				}
+			}
+
+			if errMsg != d.errMsg {
+				t.Errorf("getExecConfig returned error message %q, want %q", errMsg, d.errMsg)
+			}
+
+			if diags.HasError() {
+				return
+			}
+
+			if diff := cmp.Diff(d.want, got); diff != "" {
+				t.Errorf("getExecConfig() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExecCredentialCache(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer foo" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/version" {
+			_, _ = w.Write([]byte(`{"gitVersion":"v1.35.0"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"foo","namespace":"default"}}`))
+	}))
+	defer srv.Close()
+
+	for _, k := range []string{"KUBE_IN_CLUSTER", "KUBERNETES_SERVICE_HOST", "KUBE_CONFIG_PATHS", "KUBE_CONFIG_PATH", "KUBE_CONFIG_RAW", "KUBE_HOST", "KUBE_TOKEN"} {
+		t.Setenv(k, "")
+	}
+
+	execInfo := filepath.Join(t.TempDir(), "exec-info")
+	model := &K8sProviderModel{
+		Host:                 types.StringValue(srv.URL),
+		ClusterCACertificate: types.StringValue(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))),
+		Exec: &ExecConfigModel{
+			APIVersion: types.StringValue("client.authentication.k8s.io/v1"),
+			Command:    types.StringValue("/bin/sh"),
+			Args: types.ListValueMust(types.StringType, []attr.Value{
+				types.StringValue("-c"),
+				types.StringValue(`echo "$KUBERNETES_EXEC_INFO" >> "$EXEC_INFO" && echo '{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"foo"}}'`),
+			}),
+			Env:                types.MapValueMust(types.StringType, map[string]attr.Value{"EXEC_INFO": types.StringValue(execInfo)}),
+			InteractiveMode:    types.StringValue("Never"),
+			ProvideClusterInfo: types.BoolValue(true),
+		},
+	}
+
+	restConfig, diags := getRestClientConfig(t.Context(), model)
+	if diags.HasError() {
+		t.Fatalf("getRestClientConfig returned unexpected errors: %v", diags)
+	}
+
+	for range 2 {
+		client := NewK8sProviderClient(restConfig)
+
+		dc, err := client.DiscoveryClient()
+		if err != nil {
+			t.Fatalf("failed to create discovery client: %v", err)
+		}
+
+		if _, err := dc.ServerVersion(); err != nil {
+			t.Fatalf("failed to get server version: %v", err)
+		}
+
+		kc, err := client.DynamicClient()
+		if err != nil {
+			t.Fatalf("failed to create dynamic client: %v", err)
+		}
+
+		gvr := apimachineryschema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+		if _, err := kc.Resource(gvr).Namespace("default").Get(t.Context(), "foo", metav1.GetOptions{}); err != nil {
+			t.Fatalf("failed to get config map: %v", err)
+		}
+	}
+
+	b, err := os.ReadFile(execInfo)
+	if err != nil {
+		t.Fatalf("failed to read exec info: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 1 {
+		t.Errorf("exec plugin ran %d times, want 1", len(lines))
+	}
+
+	if !strings.Contains(lines[0], srv.URL) {
+		t.Errorf("exec plugin got KUBERNETES_EXEC_INFO %q, want it to contain the server %q", lines[0], srv.URL)
+	}
+}
+
+func TestSetOIDCAuth(t *testing.T) {
+	t.Parallel()
+
+	oidc := &OIDCModel{
+		IssuerURL:    types.StringValue("https://issuer.example.com"),
+		ClientID:     types.StringValue("client"),
+		RefreshToken: types.StringValue("foo"),
+	}
+
+	for _, d := range []struct {
+		testName string
+		model    *K8sProviderModel
+		config   rest.Config
+		wantWrap bool
+		errMsg   string
+	}{
+		{
+			testName: "defaults",
+			model:    &K8sProviderModel{},
+		},
+		{
+			testName: "oidc",
+			model:    &K8sProviderModel{OIDC: oidc},
+			wantWrap: true,
+		},
+		{
+			testName: "oidc_with_token",
+			model:    &K8sProviderModel{OIDC: oidc},
+			config:   rest.Config{BearerToken: "foo"},
+			errMsg:   "Conflicting OIDC config.",
+		},
+		{
+			testName: "oidc_with_basic_auth",
+			model:    &K8sProviderModel{OIDC: oidc},
+			config:   rest.Config{Username: "foo", Password: "bar"},
+			errMsg:   "Conflicting OIDC config.",
+		},
+		{
+			testName: "oidc_with_client_certificate",
+			model:    &K8sProviderModel{OIDC: oidc},
+			config:   rest.Config{TLSClientConfig: rest.TLSClientConfig{CertFile: "foo.crt", KeyFile: "foo.key"}},
+			errMsg:   "Conflicting OIDC config.",
+		},
+		{
+			testName: "oidc_with_exec",
+			model:    &K8sProviderModel{OIDC: oidc},
+			config:   rest.Config{ExecProvider: &clientcmdapi.ExecConfig{Command: "foo"}},
+			errMsg:   "Conflicting OIDC config.",
+		},
+		{
+			testName: "oidc_without_tokens",
+			model: &K8sProviderModel{OIDC: &OIDCModel{
+				IssuerURL: types.StringValue("https://issuer.example.com"),
+				ClientID:  types.StringValue("client"),
+			}},
+			errMsg: "Invalid OIDC config.",
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			config := &d.config
+			diags := setOIDCAuth(d.model, config)
+
+			var errMsg string
+			if diags.HasError() {
+				for i, diag := range diags.Errors() {
+					if i == 0 {
+						errMsg = diag.Summary()
+						continue
+					}
+					errMsg = fmt.Sprintf("%s: %s", errMsg, diag.Summary())
+				}
+			}
+
+			if errMsg != d.errMsg {
+				t.Errorf("setOIDCAuth returned error message %q, want %q", errMsg, d.errMsg)
+			}
+
+			if diags.HasError() {
+				return
+			}
+
+			if gotWrap := config.WrapTransport != nil; gotWrap != d.wantWrap {
+				t.Errorf("setOIDCAuth set OIDC transport %t, want %t", gotWrap, d.wantWrap)
+			}
+		})
+	}
+}
@@ -0,0 +1,567 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/terr4m/terraform-provider-k8s/internal/k8sutils"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var (
+	_ resource.Resource                   = &ManifestResource{}
+	_ resource.ResourceWithConfigure      = &ManifestResource{}
+	_ resource.ResourceWithValidateConfig = &ManifestResource{}
+	_ resource.ResourceWithModifyPlan     = &ManifestResource{}
+)
+
+// manifestObjectAttrTypes are the attribute types of a manifest object.
+var manifestObjectAttrTypes = map[string]attr.Type{
+	"api_version": types.StringType,
+	"kind":        types.StringType,
+	"namespace":   types.StringType,
+	"name":        types.StringType,
+}
+
+// NewManifestResource creates a new manifest resource.
+func NewManifestResource() resource.Resource {
+	return &ManifestResource{}
+}
+
+// ManifestResource defines the resource implementation.
+type ManifestResource struct {
+	providerData *K8sProviderData
+}
+
+// ManifestResourceModel describes the resource data model.
+type ManifestResourceModel struct {
+	Content       types.String   `tfsdk:"content"`
+	File          types.String   `tfsdk:"file"`
+	ContentSHA256 types.String   `tfsdk:"content_sha256"`
+	Objects       types.List     `tfsdk:"objects"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+}
+
+// ManifestObjectModel describes an object applied from the manifest.
+type ManifestObjectModel struct {
+	APIVersion types.String `tfsdk:"api_version"`
+	Kind       types.String `tfsdk:"kind"`
+	Namespace  types.String `tfsdk:"namespace"`
+	Name       types.String `tfsdk:"name"`
+}
+
+// newManifestObjectModel creates a manifest object model for the given object.
+func newManifestObjectModel(obj *unstructured.Unstructured) ManifestObjectModel {
+	namespace := types.StringNull()
+	if len(obj.GetNamespace()) > 0 {
+		namespace = types.StringValue(obj.GetNamespace())
+	}
+
+	return ManifestObjectModel{
+		APIVersion: types.StringValue(obj.GetAPIVersion()),
+		Kind:       types.StringValue(obj.GetKind()),
+		Namespace:  namespace,
+		Name:       types.StringValue(obj.GetName()),
+	}
+}
+
+// object returns an unstructured object identified by the model.
+func (m ManifestObjectModel) object() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(m.APIVersion.ValueString())
+	obj.SetKind(m.Kind.ValueString())
+	obj.SetNamespace(m.Namespace.ValueString())
+	obj.SetName(m.Name.ValueString())
+
+	return obj
+}
+
+// Metadata returns the resource metadata.
+func (r *ManifestResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_manifest", req.ProviderTypeName)
+}
+
+// Schema returns the resource schema.
+func (r *ManifestResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "_Kubernetes_ manifest TF resource; the objects in a multi-document YAML or JSON stream are applied to the API server using server-side apply. Namespaces are applied first, followed by custom resource definitions and then all other objects in the order they appear in the stream. Objects removed from the stream are deleted when the resource is updated.",
+		Attributes: map[string]schema.Attribute{
+			"content": schema.StringAttribute{
+				MarkdownDescription: "Multi-document YAML or JSON stream of the objects to apply; exactly one of `content` or `file` must be set. Each object must contain `apiVersion`, `kind` and `metadata.name`, and `metadata.namespace` if the object is namespaced. This is sensitive as the stream can contain secrets; changes are shown through `content_sha256` and `objects`.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file containing a multi-document YAML or JSON stream of the objects to apply; exactly one of `content` or `file` must be set.",
+				Optional:            true,
+			},
+			"content_sha256": schema.StringAttribute{
+				MarkdownDescription: "SHA256 checksum of the applied content; this is used to detect changes to the content of `file`, and shows changes to `content` as it is sensitive.",
+				Computed:            true,
+			},
+			"objects": schema.ListNestedAttribute{
+				MarkdownDescription: "Objects applied from the manifest, in the order they were applied.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"api_version": schema.StringAttribute{
+							MarkdownDescription: "API version of the object.",
+							Computed:            true,
+						},
+						"kind": schema.StringAttribute{
+							MarkdownDescription: "Kind of the object.",
+							Computed:            true,
+						},
+						"namespace": schema.StringAttribute{
+							MarkdownDescription: "Namespace of the object.",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the object.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create:            true,
+				CreateDescription: "Timeout for creating the resource; this defaults to the provider value if not set. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
+				Read:              true,
+				ReadDescription:   "Timeout for reading the resource; this defaults to the provider value if not set. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
+				Update:            true,
+				UpdateDescription: "Timeout for updating the resource; this defaults to the provider value if not set. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
+				Delete:            true,
+				DeleteDescription: "Timeout for deleting the resource; this defaults to the provider value if not set. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
+			}),
+		},
+	}
+}
+
+// Configure configures the resource.
+func (r *ManifestResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*K8sProviderData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected resource provider data.", fmt.Sprintf("expected *K8sProviderData, got: %T", req.ProviderData))
+		return
+	}
+
+	r.providerData = providerData
+}
+
+// ValidateConfig validates the resource configuration.
+func (r *ManifestResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data ManifestResourceModel
+
+	if resp.Diagnostics.Append(req.Config.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Content.IsUnknown() || data.File.IsUnknown() {
+		return
+	}
+
+	if data.Content.IsNull() == data.File.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("content"), "Invalid manifest configuration.", "exactly one of content or file must be set")
+	}
+}
+
+// Create creates the resource.
+func (r *ManifestResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ManifestResourceModel
+
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Create(ctx, r.providerData.DefaultTimeouts.Create)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp.Diagnostics.Append(r.apply(ctx, &data, nil, types.StringNull())...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read reads the resource; objects which no longer exist are removed from the state so they are applied again.
+func (r *ManifestResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ManifestResourceModel
+
+	if resp.Diagnostics.Append(req.State.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	objs, diags := manifestObjects(ctx, data.Objects)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Read(ctx, r.providerData.DefaultTimeouts.Read)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	existing := make([]ManifestObjectModel, 0, len(objs))
+	for _, o := range objs {
+		obj := o.object()
+
+		ri, err := getObjectResourceInterface(r.providerData.Client, obj)
+		if meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			resp.Diagnostics.AddError("Failed to configure resource interface.", fmt.Sprintf("%s: %s", objectString(obj), err.Error()))
+			return
+		}
+
+		_, err = ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			resp.Diagnostics.AddError("Failed to get resource.", fmt.Sprintf("%s: %s", objectString(obj), err.Error()))
+			return
+		}
+
+		existing = append(existing, o)
+	}
+
+	if len(objs) > 0 && len(existing) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.Objects, diags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: manifestObjectAttrTypes}, existing)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update updates the resource.
+func (r *ManifestResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ManifestResourceModel
+
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state ManifestResourceModel
+
+	if resp.Diagnostics.Append(req.State.Get(ctx, &state)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Update(ctx, r.providerData.DefaultTimeouts.Update)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	prior, diags := manifestObjects(ctx, state.Objects)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &data, prior, state.ContentSHA256)...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete deletes the resource.
+func (r *ManifestResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ManifestResourceModel
+
+	if resp.Diagnostics.Append(req.State.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	objs, diags := manifestObjects(ctx, data.Objects)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Delete(ctx, r.providerData.DefaultTimeouts.Delete)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp.Diagnostics.Append(r.delete(ctx, objs)...)
+}
+
+// ModifyPlan modifies the resource plan by decoding the manifest content to plan the applied objects.
+func (r *ManifestResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data ManifestResourceModel
+
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Content.IsUnknown() || data.File.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content_sha256"), types.StringUnknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("objects"), types.ListUnknown(types.ObjectType{AttrTypes: manifestObjectAttrTypes}))...)
+		return
+	}
+
+	content, diags := readManifestContent(&data)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	objs, diags := decodeManifestContent(content)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	objects, diags := manifestObjectsValue(ctx, objs)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content_sha256"), types.StringValue(contentSHA256(content)))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("objects"), objects)...)
+}
+
+// apply applies the objects in the manifest and deletes the prior objects which are no longer part of it; the model is
+// updated with the tracked objects, which include the prior objects and the objects applied so far if an error occurs.
+func (r *ManifestResource) apply(ctx context.Context, data *ManifestResourceModel, prior []ManifestObjectModel, priorChecksum types.String) (diagnostics diag.Diagnostics) {
+	tracked := slices.Clone(prior)
+	checksum := priorChecksum
+
+	defer func() {
+		objects, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: manifestObjectAttrTypes}, tracked)
+		diagnostics.Append(diags...)
+
+		data.Objects = objects
+		data.ContentSHA256 = checksum
+	}()
+
+	content, diags := readManifestContent(data)
+	if diagnostics.Append(diags...); diagnostics.HasError() {
+		return diagnostics
+	}
+
+	if !data.ContentSHA256.IsUnknown() && data.ContentSHA256.ValueString() != contentSHA256(content) {
+		diagnostics.AddError("Manifest content changed.", "the manifest content changed after the plan was created")
+		return diagnostics
+	}
+
+	objs, diags := decodeManifestContent(content)
+	if diagnostics.Append(diags...); diagnostics.HasError() {
+		return diagnostics
+	}
+
+	opts := metav1.ApplyOptions{
+		FieldManager: r.providerData.FieldManager.Name,
+		Force:        r.providerData.FieldManager.ForceConflicts,
+	}
+
+	applied := make([]ManifestObjectModel, 0, len(objs))
+	appliedKeys := make(map[string]bool, len(objs))
+	for _, obj := range objs {
+		// The resource might be defined by a custom resource definition applied earlier.
+		ri, err := waitForObjectResourceInterface(ctx, r.providerData.Client, obj)
+		if err != nil {
+			diagnostics.AddError("Failed to configure resource interface.", fmt.Sprintf("%s: %s", objectString(obj), err.Error()))
+			return diagnostics
+		}
+
+		o, err := ri.Apply(ctx, obj.GetName(), obj, opts)
+		if err != nil {
+			diagnostics.AddError("Failed to apply resource.", fmt.Sprintf("%s: %s", objectString(obj), err.Error()))
+			return diagnostics
+		}
+
+		m := newManifestObjectModel(obj)
+		applied = append(applied, m)
+		appliedKeys[objectKey(obj)] = true
+		if !slices.ContainsFunc(tracked, func(t ManifestObjectModel) bool { return objectKey(t.object()) == objectKey(obj) }) {
+			tracked = append(tracked, m)
+		}
+
+		if o.GroupVersionKind().Group == "apiextensions.k8s.io" && o.GetKind() == "CustomResourceDefinition" {
+			cond := &k8sutils.WaitCondition{Conditions: []k8sutils.StatusCondition{{Type: "Established", Status: "True"}}}
+			if diagnostics.Append(waitForObject(ctx, ri, o, cond)...); diagnostics.HasError() {
+				return diagnostics
+			}
+		}
+	}
+
+	var pruned []ManifestObjectModel
+	for _, o := range prior {
+		if !appliedKeys[objectKey(o.object())] {
+			pruned = append(pruned, o)
+		}
+	}
+
+	if diagnostics.Append(r.delete(ctx, pruned)...); diagnostics.HasError() {
+		return diagnostics
+	}
+
+	tracked = applied
+	checksum = types.StringValue(contentSHA256(content))
+
+	return diagnostics
+}
+
+// delete deletes the objects in the reverse order to which they were applied and waits for them to be removed.
+func (r *ManifestResource) delete(ctx context.Context, objs []ManifestObjectModel) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	for _, o := range slices.Backward(objs) {
+		obj := o.object()
+
+		ri, err := getObjectResourceInterface(r.providerData.Client, obj)
+		if meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			diagnostics.AddError("Failed to configure resource interface.", fmt.Sprintf("%s: %s", objectString(obj), err.Error()))
+			return diagnostics
+		}
+
+		err = ri.Delete(ctx, obj.GetName(), metav1.DeleteOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			diagnostics.AddError("Failed to delete resource.", fmt.Sprintf("%s: %s", objectString(obj), err.Error()))
+			return diagnostics
+		}
+
+		err = wait.PollUntilContextCancel(ctx, time.Second, true, func(ctx context.Context) (bool, error) {
+			_, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+			if errors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		})
+		if err != nil {
+			diagnostics.AddError("Failed to wait for resource deletion.", fmt.Sprintf("%s: %s", objectString(obj), err.Error()))
+			return diagnostics
+		}
+	}
+
+	return diagnostics
+}
+
+// readManifestContent returns the manifest content, reading it from the file if set.
+func readManifestContent(data *ManifestResourceModel) (string, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	if data.File.IsNull() {
+		return data.Content.ValueString(), diagnostics
+	}
+
+	b, err := os.ReadFile(data.File.ValueString())
+	if err != nil {
+		diagnostics.AddAttributeError(path.Root("file"), "Failed to read manifest file.", err.Error())
+		return "", diagnostics
+	}
+
+	return string(b), diagnostics
+}
+
+// decodeManifestContent decodes the manifest content into objects sorted into the order they need to be applied in.
+func decodeManifestContent(content string) ([]*unstructured.Unstructured, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	objs, err := k8sutils.DecodeManifests(strings.NewReader(content))
+	if err != nil {
+		diagnostics.AddError("Invalid manifest.", err.Error())
+		return nil, diagnostics
+	}
+
+	seen := make(map[string]bool, len(objs))
+	for _, obj := range objs {
+		k := objectKey(obj)
+		if seen[k] {
+			diagnostics.AddError("Invalid manifest.", fmt.Sprintf("%s is defined more than once", objectString(obj)))
+			return nil, diagnostics
+		}
+		seen[k] = true
+	}
+
+	k8sutils.SortByApplyOrder(objs)
+
+	return objs, diagnostics
+}
+
+// manifestObjects returns the manifest object models in the list value.
+func manifestObjects(ctx context.Context, l types.List) ([]ManifestObjectModel, diag.Diagnostics) {
+	var objs []ManifestObjectModel
+
+	if l.IsNull() || l.IsUnknown() {
+		return objs, nil
+	}
+
+	diags := l.ElementsAs(ctx, &objs, false)
+
+	return objs, diags
+}
+
+// manifestObjectsValue returns a list value of the manifest object models for the objects.
+func manifestObjectsValue(ctx context.Context, objs []*unstructured.Unstructured) (types.List, diag.Diagnostics) {
+	models := make([]ManifestObjectModel, 0, len(objs))
+	for _, obj := range objs {
+		models = append(models, newManifestObjectModel(obj))
+	}
+
+	return types.ListValueFrom(ctx, types.ObjectType{AttrTypes: manifestObjectAttrTypes}, models)
+}
+
+// contentSHA256 returns the hex encoded SHA256 checksum of the content.
+func contentSHA256(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// objectKey returns a key identifying the object independently of its API version.
+func objectKey(obj *unstructured.Unstructured) string {
+	gvk := obj.GroupVersionKind()
+	return fmt.Sprintf("%s/%s/%s/%s", gvk.Group, gvk.Kind, obj.GetNamespace(), obj.GetName())
+}
+
+// objectString returns a human readable description of the object.
+func objectString(obj *unstructured.Unstructured) string {
+	if len(obj.GetNamespace()) == 0 {
+		return fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+	}
+
+	return fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
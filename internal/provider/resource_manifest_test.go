@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestDecodeManifestContent(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []struct {
+		testName string
+		content  string
+		want     []string
+		wantErr  *string
+	}{
+		{
+			testName: "apply_order",
+			content: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  namespace: bar
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: bar
+`,
+			want: []string{"Namespace bar", "ConfigMap bar/foo"},
+		},
+		{
+			testName: "duplicate",
+			content: `apiVersion: v1
+kind: Namespace
+metadata:
+  name: bar
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: bar
+`,
+			wantErr: new("Namespace bar is defined more than once"),
+		},
+		{
+			testName: "invalid",
+			content: `apiVersion: v1
+kind: Namespace
+`,
+			wantErr: new("invalid document 0: the manifest must contain apiVersion, kind and metadata.name"),
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			objs, diags := decodeManifestContent(d.content)
+			if diags.HasError() {
+				if d.wantErr == nil {
+					t.Errorf("decodeManifestContent() returned unexpected error: %v", diags)
+				}
+
+				if got := diags.Errors()[0].Detail(); got != *d.wantErr {
+					t.Errorf("decodeManifestContent() returned error %q, want %q", got, *d.wantErr)
+				}
+
+				return
+			}
+
+			if d.wantErr != nil {
+				t.Errorf("decodeManifestContent() returned no error, want %q", *d.wantErr)
+			}
+
+			got := make([]string, 0, len(objs))
+			for _, o := range objs {
+				got = append(got, objectString(o))
+			}
+
+			if diff := cmp.Diff(d.want, got); diff != "" {
+				t.Errorf("decodeManifestContent() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAccManifestResource(t *testing.T) {
+	t.Run("content", func(t *testing.T) {
+		namespace := "tf-acc-manifest"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`resource "k8s_manifest" "test" {
+  content = <<-EOT
+    apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: foo
+      namespace: %[1]s
+    data:
+      foo: bar
+    ---
+    apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: bar
+      namespace: %[1]s
+    ---
+    apiVersion: v1
+    kind: Namespace
+    metadata:
+      name: %[1]s
+  EOT
+}`, namespace),
+					ConfigStateChecks: []statecheck.StateCheck{
+						statecheck.ExpectKnownValue("k8s_manifest.test", tfjsonpath.New("objects"), knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.ObjectExact(map[string]knownvalue.Check{
+								"api_version": knownvalue.StringExact("v1"),
+								"kind":        knownvalue.StringExact("Namespace"),
+								"namespace":   knownvalue.Null(),
+								"name":        knownvalue.StringExact(namespace),
+							}),
+							knownvalue.ObjectExact(map[string]knownvalue.Check{
+								"api_version": knownvalue.StringExact("v1"),
+								"kind":        knownvalue.StringExact("ConfigMap"),
+								"namespace":   knownvalue.StringExact(namespace),
+								"name":        knownvalue.StringExact("foo"),
+							}),
+							knownvalue.ObjectExact(map[string]knownvalue.Check{
+								"api_version": knownvalue.StringExact("v1"),
+								"kind":        knownvalue.StringExact("ConfigMap"),
+								"namespace":   knownvalue.StringExact(namespace),
+								"name":        knownvalue.StringExact("bar"),
+							}),
+						})),
+					},
+				},
+				{
+					Config: fmt.Sprintf(`resource "k8s_manifest" "test" {
+  content = <<-EOT
+    apiVersion: v1
+    kind: Namespace
+    metadata:
+      name: %[1]s
+    ---
+    apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: foo
+      namespace: %[1]s
+    data:
+      foo: baz
+  EOT
+}
+
+data "k8s_resources" "test" {
+  api_version    = "v1"
+  kind           = "ConfigMap"
+  namespace      = "%[1]s"
+  field_selector = "metadata.name=bar"
+
+  depends_on = [k8s_manifest.test]
+}`, namespace),
+					ConfigStateChecks: []statecheck.StateCheck{
+						statecheck.ExpectKnownValue("k8s_manifest.test", tfjsonpath.New("objects"), knownvalue.ListSizeExact(2)),
+						statecheck.ExpectKnownValue("data.k8s_resources.test", tfjsonpath.New("objects"), knownvalue.ListSizeExact(0)),
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "manifest.yaml")
+		if err := os.WriteFile(file, []byte(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: tf-acc-manifest-file
+`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`resource "k8s_manifest" "test" {
+  file = %q
+}`, file),
+					ConfigStateChecks: []statecheck.StateCheck{
+						statecheck.ExpectKnownValue("k8s_manifest.test", tfjsonpath.New("objects"), knownvalue.ListSizeExact(1)),
+						statecheck.ExpectKnownValue("k8s_manifest.test", tfjsonpath.New("content_sha256"), knownvalue.NotNull()),
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("invalid_config", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `resource "k8s_manifest" "test" {
+  content = ""
+  file    = "manifest.yaml"
+}`,
+					PlanOnly:    true,
+					ExpectError: regexp.MustCompile("Invalid manifest configuration."),
+				},
+			},
+		})
+	})
+}
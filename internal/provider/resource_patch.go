@@ -0,0 +1,400 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/terr4m/terraform-provider-k8s/internal/k8sutils"
+	"github.com/terr4m/terraform-provider-k8s/internal/tfutils"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/dynamicplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	_ resource.Resource               = &PatchResource{}
+	_ resource.ResourceWithConfigure  = &PatchResource{}
+	_ resource.ResourceWithModifyPlan = &PatchResource{}
+)
+
+// NewPatchResource creates a new patch resource.
+func NewPatchResource() resource.Resource {
+	return &PatchResource{}
+}
+
+// PatchResource defines the resource implementation.
+type PatchResource struct {
+	providerData *K8sProviderData
+}
+
+// PatchResourceModel describes the resource data model.
+type PatchResourceModel struct {
+	Manifest       types.Dynamic  `tfsdk:"manifest"`
+	FieldManager   types.String   `tfsdk:"field_manager"`
+	ForceConflicts types.Bool     `tfsdk:"force_conflicts"`
+	Result         types.Dynamic  `tfsdk:"result"`
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource metadata.
+func (r *PatchResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_patch", req.ProviderTypeName)
+}
+
+// Schema returns the resource schema.
+func (r *PatchResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "_Kubernetes_ patch TF resource; the partial manifest is applied to an existing resource using server-side apply with a dedicated field manager, so only the fields in the manifest are managed. Destroying the patch releases the ownership of the fields instead of deleting the resource; fields which aren't owned by another field manager are removed.",
+		Attributes: map[string]schema.Attribute{
+			"manifest": schema.DynamicAttribute{
+				MarkdownDescription: "Partial manifest to apply to the resource; this must contain `apiVersion`, `kind` and `metadata.name`, and `metadata.namespace` if the resource is namespaced. Changing the group, kind, namespace or name forces the patch to be replaced.",
+				Required:            true,
+				PlanModifiers: []planmodifier.Dynamic{
+					dynamicplanmodifier.RequiresReplaceIf(requiresReplaceIfIdentityChanged, "Changing the resource identity requires replacement.", "Changing the resource identity requires replacement."),
+				},
+			},
+			"field_manager": schema.StringAttribute{
+				MarkdownDescription: "Field manager name used to apply the patch; this defaults to the provider field manager name suffixed with `-patch-` and a random identifier, which is unique to each patch. Patches to the same resource must use different field managers. Changing this forces the patch to be replaced.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"force_conflicts": schema.BoolAttribute{
+				MarkdownDescription: "If `true`, the patch is applied by ignoring conflicts with other field managers; this defaults to the provider value if not set.",
+				Optional:            true,
+			},
+			"result": schema.DynamicAttribute{
+				MarkdownDescription: "Fields of the resource owned by the patch field manager; when an existing patch is updated this is planned by a server-side dry-run apply of the manifest.",
+				Computed:            true,
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create:            true,
+				CreateDescription: "Timeout for creating the patch; this defaults to the provider value if not set. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
+				Read:              true,
+				ReadDescription:   "Timeout for reading the patch; this defaults to the provider value if not set. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
+				Update:            true,
+				UpdateDescription: "Timeout for updating the patch; this defaults to the provider value if not set. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
+				Delete:            true,
+				DeleteDescription: "Timeout for deleting the patch; this defaults to the provider value if not set. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
+			}),
+		},
+	}
+}
+
+// Configure configures the resource.
+func (r *PatchResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*K8sProviderData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected resource provider data.", fmt.Sprintf("expected *K8sProviderData, got: %T", req.ProviderData))
+		return
+	}
+
+	r.providerData = providerData
+}
+
+// Create creates the resource.
+func (r *PatchResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data PatchResourceModel
+
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Create(ctx, r.providerData.DefaultTimeouts.Create)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, diags := r.apply(ctx, &data, false)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	data.Result = result
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read reads the resource.
+func (r *PatchResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PatchResourceModel
+
+	if resp.Diagnostics.Append(req.State.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	obj, diags := manifestToUnstructured(ctx, data.Manifest)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ri, err := getObjectResourceInterface(r.providerData.Client, obj)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to configure resource interface.", err.Error())
+		return
+	}
+
+	timeout, diags := data.Timeouts.Read(ctx, r.providerData.DefaultTimeouts.Read)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	o, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Failed to get resource.", err.Error())
+		return
+	}
+
+	result, diags := decodeOwnedFields(ctx, o, data.FieldManager.ValueString())
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	data.Result = result
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update updates the resource.
+func (r *PatchResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data PatchResourceModel
+
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Update(ctx, r.providerData.DefaultTimeouts.Update)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, diags := r.apply(ctx, &data, false)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	data.Result = result
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete deletes the resource by applying a manifest without any fields, which releases the ownership of all of the
+// fields managed by the patch.
+func (r *PatchResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data PatchResourceModel
+
+	if resp.Diagnostics.Append(req.State.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	obj, diags := manifestToUnstructured(ctx, data.Manifest)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ri, err := getObjectResourceInterface(r.providerData.Client, obj)
+	if meta.IsNoMatchError(err) {
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Failed to configure resource interface.", err.Error())
+		return
+	}
+
+	timeout, diags := data.Timeouts.Delete(ctx, r.providerData.DefaultTimeouts.Delete)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// The resource isn't applied if it doesn't exist, as this would create it.
+	_, err = ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Failed to get resource.", err.Error())
+		return
+	}
+
+	empty := &unstructured.Unstructured{}
+	empty.SetAPIVersion(obj.GetAPIVersion())
+	empty.SetKind(obj.GetKind())
+	empty.SetNamespace(obj.GetNamespace())
+	empty.SetName(obj.GetName())
+
+	_, err = ri.Apply(ctx, obj.GetName(), empty, metav1.ApplyOptions{FieldManager: data.FieldManager.ValueString()})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to release patch fields.", err.Error())
+		return
+	}
+}
+
+// ModifyPlan modifies the resource plan by setting the default field manager and running a server-side dry-run apply of
+// the manifest.
+func (r *PatchResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.providerData == nil {
+		return
+	}
+
+	var data PatchResourceModel
+
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.FieldManager.IsUnknown() {
+		fieldManager, err := defaultPatchFieldManager(r.providerData.FieldManager.Name)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to generate field manager.", err.Error())
+			return
+		}
+
+		data.FieldManager = types.StringValue(fieldManager)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("field_manager"), data.FieldManager)...)
+	}
+
+	manifest, err := data.Manifest.ToTerraformValue(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to convert manifest.", err.Error())
+		return
+	}
+
+	if !manifest.IsFullyKnown() || data.ForceConflicts.IsUnknown() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Read(ctx, r.providerData.DefaultTimeouts.Read)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, diags := r.apply(ctx, &data, true)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	if req.State.Raw.IsNull() || len(resp.RequiresReplace) > 0 {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("result"), result)...)
+}
+
+// defaultPatchFieldManager returns the default field manager of a patch; the provider field manager name is suffixed with
+// a random identifier so patches to the same resource don't share their fields.
+func defaultPatchFieldManager(name string) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s-patch-%s", name, hex.EncodeToString(b)), nil
+}
+
+// apply applies the patch manifest to the existing resource using server-side apply and returns the decoded fields owned
+// by the patch field manager; if dryRun is true the request isn't persisted and a result is only returned if the
+// resource exists.
+func (r *PatchResource) apply(ctx context.Context, data *PatchResourceModel, dryRun bool) (types.Dynamic, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	obj, diags := manifestToUnstructured(ctx, data.Manifest)
+	if diagnostics.Append(diags...); diagnostics.HasError() {
+		return types.DynamicUnknown(), diagnostics
+	}
+
+	var ri dynamic.ResourceInterface
+	var err error
+	if dryRun {
+		ri, err = getObjectResourceInterface(r.providerData.Client, obj)
+	} else {
+		ri, err = waitForObjectResourceInterface(ctx, r.providerData.Client, obj)
+	}
+
+	if dryRun && meta.IsNoMatchError(err) {
+		return types.DynamicUnknown(), diagnostics
+	} else if err != nil {
+		diagnostics.AddError("Failed to configure resource interface.", err.Error())
+		return types.DynamicUnknown(), diagnostics
+	}
+
+	// The patch is only applied to an existing resource, as server-side apply would otherwise create it.
+	_, err = ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if dryRun && errors.IsNotFound(err) {
+		return types.DynamicUnknown(), diagnostics
+	} else if err != nil {
+		diagnostics.AddError("Failed to get resource.", err.Error())
+		return types.DynamicUnknown(), diagnostics
+	}
+
+	force := r.providerData.FieldManager.ForceConflicts
+	if !data.ForceConflicts.IsNull() {
+		force = data.ForceConflicts.ValueBool()
+	}
+
+	opts := metav1.ApplyOptions{
+		FieldManager: data.FieldManager.ValueString(),
+		Force:        force,
+	}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+
+	o, err := ri.Apply(ctx, obj.GetName(), obj, opts)
+	if dryRun && err != nil {
+		diagnostics.AddError("Failed to dry-run apply patch.", err.Error())
+		return types.DynamicUnknown(), diagnostics
+	} else if err != nil {
+		diagnostics.AddError("Failed to apply patch.", err.Error())
+		return types.DynamicUnknown(), diagnostics
+	}
+
+	return decodeOwnedFields(ctx, o, data.FieldManager.ValueString())
+}
+
+// decodeOwnedFields decodes the fields of the object owned by the field manager into a Terraform dynamic value.
+func decodeOwnedFields(ctx context.Context, obj *unstructured.Unstructured, manager string) (types.Dynamic, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	fields, _, err := k8sutils.ExtractManagedFields(obj, manager)
+	if err != nil {
+		diagnostics.AddError("Failed to extract managed fields.", err.Error())
+		return types.DynamicUnknown(), diagnostics
+	}
+
+	return tfutils.DecodeDynamic(ctx, fields)
+}
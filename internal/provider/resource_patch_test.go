@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccPatchResource(t *testing.T) {
+	t.Run("existing_resource", func(t *testing.T) {
+		namespace := "default"
+		name := "tf-acc-patch"
+
+		configMap := fmt.Sprintf(`resource "k8s_resource" "test" {
+  manifest = {
+    apiVersion = "v1"
+    kind       = "ConfigMap"
+    metadata = {
+      namespace = "%s"
+      name      = "%s"
+    }
+    data = {
+      foo = "bar"
+    }
+  }
+}`, namespace, name)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`%s
+
+resource "k8s_patch" "test" {
+  manifest = {
+    apiVersion = "v1"
+    kind       = "ConfigMap"
+    metadata = {
+      namespace = "%s"
+      name      = "%s"
+      labels = {
+        "tf-acc-patch" = "true"
+      }
+    }
+  }
+
+  depends_on = [k8s_resource.test]
+}`, configMap, namespace, name),
+					ConfigStateChecks: []statecheck.StateCheck{
+						statecheck.ExpectKnownValue("k8s_patch.test", tfjsonpath.New("field_manager"), knownvalue.StringRegexp(regexp.MustCompile(`^terraform-provider-k8s-patch-[0-9a-f]{16}$`))),
+						statecheck.ExpectKnownValue("k8s_patch.test", tfjsonpath.New("result").AtMapKey("metadata").AtMapKey("labels").AtMapKey("tf-acc-patch"), knownvalue.StringExact("true")),
+					},
+				},
+				{
+					Config: fmt.Sprintf(`%s
+
+data "k8s_resources" "test" {
+  api_version    = "v1"
+  kind           = "ConfigMap"
+  namespace      = "%s"
+  label_selector = "tf-acc-patch=true"
+
+  depends_on = [k8s_resource.test]
+}`, configMap, namespace),
+					ConfigStateChecks: []statecheck.StateCheck{
+						statecheck.ExpectKnownValue("data.k8s_resources.test", tfjsonpath.New("objects"), knownvalue.ListSizeExact(0)),
+						statecheck.ExpectKnownValue("k8s_resource.test", tfjsonpath.New("result").AtMapKey("data").AtMapKey("foo"), knownvalue.StringExact("bar")),
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("missing_resource", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `resource "k8s_patch" "test" {
+  manifest = {
+    apiVersion = "v1"
+    kind       = "ConfigMap"
+    metadata = {
+      namespace = "default"
+      name      = "tf-acc-patch-missing"
+      labels = {
+        "tf-acc-patch" = "true"
+      }
+    }
+  }
+}`,
+					ExpectError: regexp.MustCompile("Failed to get resource."),
+				},
+			},
+		})
+	})
+}
@@ -0,0 +1,660 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/terr4m/terraform-provider-k8s/internal/k8sutils"
+	"github.com/terr4m/terraform-provider-k8s/internal/tfutils"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/dynamicplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apimachineryschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	_ resource.Resource                = &ResourceResource{}
+	_ resource.ResourceWithConfigure   = &ResourceResource{}
+	_ resource.ResourceWithModifyPlan  = &ResourceResource{}
+	_ resource.ResourceWithImportState = &ResourceResource{}
+	_ resource.ResourceWithIdentity    = &ResourceResource{}
+)
+
+// NewResourceResource creates a new resource resource.
+func NewResourceResource() resource.Resource {
+	return &ResourceResource{}
+}
+
+// ResourceResource defines the resource implementation.
+type ResourceResource struct {
+	providerData *K8sProviderData
+}
+
+// ResourceResourceModel describes the resource data model.
+type ResourceResourceModel struct {
+	Manifest    types.Dynamic      `tfsdk:"manifest"`
+	Result      types.Dynamic      `tfsdk:"result"`
+	TypedResult types.Bool         `tfsdk:"typed_result"`
+	Wait        *ResourceWaitModel `tfsdk:"wait"`
+	Timeouts    timeouts.Value     `tfsdk:"timeouts"`
+}
+
+// ResourceWaitModel describes the state the resource needs to reach after it has been applied.
+type ResourceWaitModel struct {
+	Rollout    types.Bool                   `tfsdk:"rollout"`
+	Conditions []ResourceWaitConditionModel `tfsdk:"conditions"`
+	Fields     types.Map                    `tfsdk:"fields"`
+}
+
+// ResourceWaitConditionModel describes a status condition the resource needs to have.
+type ResourceWaitConditionModel struct {
+	Type   types.String `tfsdk:"type"`
+	Status types.String `tfsdk:"status"`
+}
+
+// ResourceResourceIdentityModel describes the resource identity data model.
+type ResourceResourceIdentityModel struct {
+	APIVersion types.String `tfsdk:"api_version"`
+	Kind       types.String `tfsdk:"kind"`
+	Namespace  types.String `tfsdk:"namespace"`
+	Name       types.String `tfsdk:"name"`
+}
+
+// newResourceResourceIdentityModel creates a resource identity model for the given object.
+func newResourceResourceIdentityModel(obj *unstructured.Unstructured) *ResourceResourceIdentityModel {
+	namespace := types.StringNull()
+	if len(obj.GetNamespace()) > 0 {
+		namespace = types.StringValue(obj.GetNamespace())
+	}
+
+	return &ResourceResourceIdentityModel{
+		APIVersion: types.StringValue(obj.GetAPIVersion()),
+		Kind:       types.StringValue(obj.GetKind()),
+		Namespace:  namespace,
+		Name:       types.StringValue(obj.GetName()),
+	}
+}
+
+// Metadata returns the resource metadata.
+func (r *ResourceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_resource", req.ProviderTypeName)
+
+	// The API version of a resource can be changed without replacing it.
+	resp.ResourceBehavior.MutableIdentity = true
+}
+
+// Schema returns the resource schema.
+func (r *ResourceResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "_Kubernetes_ resource TF resource; the manifest is applied to the API server using server-side apply.",
+		Attributes: map[string]schema.Attribute{
+			"manifest": schema.DynamicAttribute{
+				MarkdownDescription: "Manifest of the resource to apply; this must contain `apiVersion`, `kind` and `metadata.name`, and `metadata.namespace` if the resource is namespaced. Changing the group, kind, namespace or name forces the resource to be replaced.",
+				Required:            true,
+				PlanModifiers: []planmodifier.Dynamic{
+					dynamicplanmodifier.RequiresReplaceIf(requiresReplaceIfIdentityChanged, "Changing the resource identity requires replacement.", "Changing the resource identity requires replacement."),
+				},
+			},
+			"result": schema.DynamicAttribute{
+				MarkdownDescription: "Resource object returned by the API server; when an existing resource is updated this is planned by a server-side dry-run apply of the manifest. The following fields are not returned; `status`, `metadata.creationTimestamp`, `metadata.generation`, `metadata.resourceVersion`, `metadata.selfLink`, `metadata.managedFields[*].time`.",
+				Computed:            true,
+			},
+			"typed_result": schema.BoolAttribute{
+				MarkdownDescription: "If `true`, `result` is decoded using the _OpenAPI_ schema of the resource kind so lists and maps have a single element type, with missing object fields set to `null`; this defaults to `false`. Fields without a schema, such as those preserving unknown fields, are decoded in the same way as when this is `false`.",
+				Optional:            true,
+			},
+			"wait": schema.SingleNestedAttribute{
+				MarkdownDescription: "State the resource needs to reach after it has been created or updated; the resource is watched until all of the configured requirements are met or the create or update timeout expires.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"rollout": schema.BoolAttribute{
+						MarkdownDescription: "If `true`, wait for the resource to be rolled out; deployments, stateful sets and daemon sets need all of their replicas to be updated and available, jobs need to be complete, and other resources need their `status.observedGeneration` (if set) to match `metadata.generation`.",
+						Optional:            true,
+					},
+					"conditions": schema.ListNestedAttribute{
+						MarkdownDescription: "Conditions the resource needs to have in `status.conditions`.",
+						Optional:            true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"type": schema.StringAttribute{
+									MarkdownDescription: "Type of the condition.",
+									Required:            true,
+								},
+								"status": schema.StringAttribute{
+									MarkdownDescription: "Status of the condition, such as `True`.",
+									Required:            true,
+								},
+							},
+						},
+					},
+					"fields": schema.MapAttribute{
+						MarkdownDescription: "Map of field paths to the values they need to have; field names are separated by dots, list indexes are in brackets (e.g. `status.containerStatuses[0].ready`) and field names containing dots are quoted in brackets (e.g. `metadata.labels[\"app.kubernetes.io/name\"]`). Non-string values are compared using their JSON representation.",
+						Optional:            true,
+						ElementType:         types.StringType,
+					},
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create:            true,
+				CreateDescription: "Timeout for creating the resource; this defaults to the provider value if not set. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
+				Read:              true,
+				ReadDescription:   "Timeout for reading the resource; this defaults to the provider value if not set. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
+				Update:            true,
+				UpdateDescription: "Timeout for updating the resource; this defaults to the provider value if not set. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
+				Delete:            true,
+				DeleteDescription: "Timeout for deleting the resource; this defaults to the provider value if not set. This should be a string that can be [parsed as a duration] (https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as `30s` or `2h45m`. Valid time units are `s` (seconds), `m` (minutes), `h` (hours).",
+			}),
+		},
+	}
+}
+
+// IdentitySchema returns the resource identity schema.
+func (r *ResourceResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"api_version": identityschema.StringAttribute{
+				Description:       "API version of the resource.",
+				RequiredForImport: true,
+			},
+			"kind": identityschema.StringAttribute{
+				Description:       "Kind of the resource.",
+				RequiredForImport: true,
+			},
+			"namespace": identityschema.StringAttribute{
+				Description:       "Namespace of the resource; this must be set for namespaced resources and unset for cluster scoped resources.",
+				OptionalForImport: true,
+			},
+			"name": identityschema.StringAttribute{
+				Description:       "Name of the resource.",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
+// Configure configures the resource.
+func (r *ResourceResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*K8sProviderData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected resource provider data.", fmt.Sprintf("expected *K8sProviderData, got: %T", req.ProviderData))
+		return
+	}
+
+	r.providerData = providerData
+}
+
+// Create creates the resource.
+func (r *ResourceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ResourceResourceModel
+
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Create(ctx, r.providerData.DefaultTimeouts.Create)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	obj, result, diags := r.apply(ctx, data.Manifest, data.TypedResult, false)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	data.Result = result
+
+	if data.Wait != nil {
+		if resp.Diagnostics.Append(r.wait(ctx, obj, data.Wait)...); resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	if resp.Identity != nil {
+		resp.Diagnostics.Append(resp.Identity.Set(ctx, newResourceResourceIdentityModel(obj))...)
+	}
+}
+
+// Read reads the resource.
+func (r *ResourceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ResourceResourceModel
+
+	if resp.Diagnostics.Append(req.State.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	obj, diags := manifestToUnstructured(ctx, data.Manifest)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ri, err := getObjectResourceInterface(r.providerData.Client, obj)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to configure resource interface.", err.Error())
+		return
+	}
+
+	timeout, diags := data.Timeouts.Read(ctx, r.providerData.DefaultTimeouts.Read)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	o, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Failed to get resource.", err.Error())
+		return
+	}
+
+	result, diags := r.decodeResult(ctx, o, data.TypedResult)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	data.Result = result
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	if resp.Identity != nil {
+		resp.Diagnostics.Append(resp.Identity.Set(ctx, newResourceResourceIdentityModel(o))...)
+	}
+}
+
+// Update updates the resource.
+func (r *ResourceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ResourceResourceModel
+
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Update(ctx, r.providerData.DefaultTimeouts.Update)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	obj, result, diags := r.apply(ctx, data.Manifest, data.TypedResult, false)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	data.Result = result
+
+	if data.Wait != nil {
+		if resp.Diagnostics.Append(r.wait(ctx, obj, data.Wait)...); resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	if resp.Identity != nil {
+		resp.Diagnostics.Append(resp.Identity.Set(ctx, newResourceResourceIdentityModel(obj))...)
+	}
+}
+
+// Delete deletes the resource.
+func (r *ResourceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ResourceResourceModel
+
+	if resp.Diagnostics.Append(req.State.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	obj, diags := manifestToUnstructured(ctx, data.Manifest)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ri, err := getObjectResourceInterface(r.providerData.Client, obj)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to configure resource interface.", err.Error())
+		return
+	}
+
+	timeout, diags := data.Timeouts.Delete(ctx, r.providerData.DefaultTimeouts.Delete)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err = ri.Delete(ctx, obj.GetName(), metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Failed to delete resource.", err.Error())
+		return
+	}
+
+	err = wait.PollUntilContextCancel(ctx, time.Second, true, func(ctx context.Context) (bool, error) {
+		_, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to wait for resource deletion.", err.Error())
+		return
+	}
+}
+
+// ImportState imports the resource from either its identity or an ID in the form `apiVersion//kind//namespace//name`, or
+// `apiVersion//kind//name` for cluster scoped resources; the manifest is reconstructed from the fields owned by the
+// provider field manager.
+func (r *ResourceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var id manifestIdentity
+	if len(req.ID) > 0 {
+		var err error
+		id, err = parseImportID(req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid import ID.", err.Error())
+			return
+		}
+	} else {
+		var identity ResourceResourceIdentityModel
+		if resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...); resp.Diagnostics.HasError() {
+			return
+		}
+
+		id = manifestIdentity{
+			APIVersion: identity.APIVersion.ValueString(),
+			Kind:       identity.Kind.ValueString(),
+			Namespace:  identity.Namespace.ValueString(),
+			Name:       identity.Name.ValueString(),
+		}
+	}
+
+	gvk, err := k8sutils.ParseGVK(id.APIVersion, id.Kind)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse GVK.", err.Error())
+		return
+	}
+
+	rm, err := r.providerData.Client.RESTMapper()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to configure REST mapper.", err.Error())
+		return
+	}
+
+	m, err := k8sutils.GetMapping(rm, gvk)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to get REST mapping.", err.Error())
+		return
+	}
+
+	if m.Scope.Name() != meta.RESTScopeNameNamespace && len(id.Namespace) > 0 {
+		resp.Diagnostics.AddError("Invalid resource namespace.", fmt.Sprintf("%s is cluster scoped so a namespace can't be set", gvk.Kind))
+		return
+	}
+
+	dc, err := r.providerData.Client.DynamicClient()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to configure dynamic client.", err.Error())
+		return
+	}
+
+	ri, err := k8sutils.GetResourceInterface(dc, m, true, id.Namespace)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to configure resource interface.", err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.providerData.DefaultTimeouts.Read)
+	defer cancel()
+
+	o, err := ri.Get(ctx, id.Name, metav1.GetOptions{})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to get resource.", err.Error())
+		return
+	}
+
+	fields, owned, err := k8sutils.ExtractManagedFields(o, r.providerData.FieldManager.Name)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to extract managed fields.", err.Error())
+		return
+	}
+
+	if !owned {
+		resp.Diagnostics.AddWarning("No managed fields.", fmt.Sprintf("the field manager %q doesn't own any fields of the resource; the imported manifest only contains the resource identity", r.providerData.FieldManager.Name))
+	}
+
+	manifest, diags := tfutils.DecodeDynamic(ctx, fields)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	result, diags := decodeObject(ctx, o)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("manifest"), manifest)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("result"), result)...)
+
+	if resp.Identity != nil {
+		resp.Diagnostics.Append(resp.Identity.Set(ctx, newResourceResourceIdentityModel(o))...)
+	}
+}
+
+// ModifyPlan modifies the resource plan by running a server-side dry-run apply of the manifest.
+func (r *ResourceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.providerData == nil {
+		return
+	}
+
+	var data ResourceResourceModel
+
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	manifest, err := data.Manifest.ToTerraformValue(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to convert manifest.", err.Error())
+		return
+	}
+
+	if !manifest.IsFullyKnown() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Read(ctx, r.providerData.DefaultTimeouts.Read)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	_, result, diags := r.apply(ctx, data.Manifest, data.TypedResult, true)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A new object's server populated fields such as the UID are only known after it has been created.
+	if req.State.Raw.IsNull() || len(resp.RequiresReplace) > 0 {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("result"), result)...)
+}
+
+// apply applies the manifest to the API server using server-side apply and returns the resulting object and its decoded
+// value; if dryRun is true the request isn't persisted and a result is only returned if the resource can be resolved and
+// its namespace exists.
+func (r *ResourceResource) apply(ctx context.Context, manifest types.Dynamic, typed types.Bool, dryRun bool) (*unstructured.Unstructured, types.Dynamic, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	obj, diags := manifestToUnstructured(ctx, manifest)
+	if diagnostics.Append(diags...); diagnostics.HasError() {
+		return nil, types.DynamicUnknown(), diagnostics
+	}
+
+	var ri dynamic.ResourceInterface
+	var err error
+	if dryRun {
+		ri, err = getObjectResourceInterface(r.providerData.Client, obj)
+	} else {
+		ri, err = waitForObjectResourceInterface(ctx, r.providerData.Client, obj)
+	}
+
+	if dryRun && meta.IsNoMatchError(err) {
+		return nil, types.DynamicUnknown(), diagnostics
+	} else if err != nil {
+		diagnostics.AddError("Failed to configure resource interface.", err.Error())
+		return nil, types.DynamicUnknown(), diagnostics
+	}
+
+	opts := metav1.ApplyOptions{
+		FieldManager: r.providerData.FieldManager.Name,
+		Force:        r.providerData.FieldManager.ForceConflicts,
+	}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+
+	o, err := ri.Apply(ctx, obj.GetName(), obj, opts)
+	if dryRun && errors.IsNotFound(err) {
+		return nil, types.DynamicUnknown(), diagnostics
+	} else if dryRun && err != nil {
+		diagnostics.AddError("Failed to dry-run apply resource.", err.Error())
+		return nil, types.DynamicUnknown(), diagnostics
+	} else if err != nil {
+		diagnostics.AddError("Failed to apply resource.", err.Error())
+		return nil, types.DynamicUnknown(), diagnostics
+	}
+
+	result, diags := r.decodeResult(ctx, o, typed)
+	diagnostics.Append(diags...)
+
+	return o, result, diagnostics
+}
+
+// decodeResult decodes an object returned by the API server into the result value; if typed is true the OpenAPI schema
+// of the kind is used.
+func (r *ResourceResource) decodeResult(ctx context.Context, obj *unstructured.Unstructured, typed types.Bool) (types.Dynamic, diag.Diagnostics) {
+	if typed.ValueBool() {
+		return decodeTypedObject(ctx, r.providerData.Client, obj)
+	}
+
+	return decodeObject(ctx, obj)
+}
+
+// wait waits for the applied object to satisfy the wait configuration.
+func (r *ResourceResource) wait(ctx context.Context, obj *unstructured.Unstructured, m *ResourceWaitModel) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	cond, diags := m.waitCondition(ctx)
+	if diagnostics.Append(diags...); diagnostics.HasError() {
+		return diagnostics
+	}
+
+	ri, err := getObjectResourceInterface(r.providerData.Client, obj)
+	if err != nil {
+		diagnostics.AddError("Failed to configure resource interface.", err.Error())
+		return diagnostics
+	}
+
+	diagnostics.Append(waitForObject(ctx, ri, obj, cond)...)
+
+	return diagnostics
+}
+
+// waitCondition converts the wait model into a wait condition.
+func (m *ResourceWaitModel) waitCondition(ctx context.Context) (*k8sutils.WaitCondition, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	cond := &k8sutils.WaitCondition{
+		Rollout:    m.Rollout.ValueBool(),
+		Conditions: make([]k8sutils.StatusCondition, 0, len(m.Conditions)),
+	}
+
+	for _, c := range m.Conditions {
+		cond.Conditions = append(cond.Conditions, k8sutils.StatusCondition{Type: c.Type.ValueString(), Status: c.Status.ValueString()})
+	}
+
+	if !m.Fields.IsNull() && !m.Fields.IsUnknown() {
+		diagnostics.Append(m.Fields.ElementsAs(ctx, &cond.Fields, false)...)
+	}
+
+	return cond, diagnostics
+}
+
+// requiresReplaceIfIdentityChanged requires the resource to be replaced if the group, kind, namespace or name of the manifest changes.
+func requiresReplaceIfIdentityChanged(_ context.Context, req planmodifier.DynamicRequest, resp *dynamicplanmodifier.RequiresReplaceIfFuncResponse) {
+	state, ok := getManifestIdentity(req.StateValue)
+	if !ok {
+		return
+	}
+
+	plan, ok := getManifestIdentity(req.PlanValue)
+	if !ok {
+		resp.RequiresReplace = true
+		return
+	}
+
+	stateGV, err := apimachineryschema.ParseGroupVersion(state.APIVersion)
+	if err != nil {
+		resp.RequiresReplace = true
+		return
+	}
+
+	planGV, err := apimachineryschema.ParseGroupVersion(plan.APIVersion)
+	if err != nil {
+		resp.RequiresReplace = true
+		return
+	}
+
+	resp.RequiresReplace = stateGV.Group != planGV.Group || state.Kind != plan.Kind || state.Namespace != plan.Namespace || state.Name != plan.Name
+}
+
+// parseImportID parses an import ID in the form `apiVersion//kind//namespace//name` or `apiVersion//kind//name`.
+func parseImportID(id string) (manifestIdentity, error) {
+	var mi manifestIdentity
+
+	parts := strings.Split(id, "//")
+	switch len(parts) {
+	case 3:
+		mi = manifestIdentity{APIVersion: parts[0], Kind: parts[1], Name: parts[2]}
+	case 4:
+		mi = manifestIdentity{APIVersion: parts[0], Kind: parts[1], Namespace: parts[2], Name: parts[3]}
+	}
+
+	if len(mi.APIVersion) == 0 || len(mi.Kind) == 0 || len(mi.Name) == 0 {
+		return manifestIdentity{}, fmt.Errorf("expected an ID in the form apiVersion//kind//namespace//name or apiVersion//kind//name, got: %q", id)
+	}
+
+	return mi, nil
+}
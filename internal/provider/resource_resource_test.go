@@ -0,0 +1,347 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestParseImportID(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []struct {
+		testName string
+		id       string
+		want     manifestIdentity
+		errMsg   string
+	}{
+		{
+			testName: "namespaced",
+			id:       "apps/v1//Deployment//default//foo",
+			want:     manifestIdentity{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "foo"},
+		},
+		{
+			testName: "cluster_scoped",
+			id:       "v1//Namespace//foo",
+			want:     manifestIdentity{APIVersion: "v1", Kind: "Namespace", Name: "foo"},
+		},
+		{
+			testName: "empty_namespace",
+			id:       "v1//Namespace////foo",
+			want:     manifestIdentity{APIVersion: "v1", Kind: "Namespace", Name: "foo"},
+		},
+		{
+			testName: "too_few_parts",
+			id:       "v1//Namespace",
+			errMsg:   `expected an ID in the form apiVersion//kind//namespace//name or apiVersion//kind//name, got: "v1//Namespace"`,
+		},
+		{
+			testName: "empty_name",
+			id:       "v1//ConfigMap//default//",
+			errMsg:   `expected an ID in the form apiVersion//kind//namespace//name or apiVersion//kind//name, got: "v1//ConfigMap//default//"`,
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseImportID(d.id)
+
+			if diff := cmp.Diff(d.want, got); diff != "" {
+				t.Errorf("parseImportID() mismatch (-want +got):\n%s", diff)
+			}
+
+			var errMsg string
+			if err != nil {
+				errMsg = err.Error()
+			}
+
+			if errMsg != d.errMsg {
+				t.Errorf("parseImportID() returned error message %q, want %q", errMsg, d.errMsg)
+			}
+		})
+	}
+}
+
+func TestAccResourceResource(t *testing.T) {
+	t.Run("namespaced_resource", func(t *testing.T) {
+		namespace := "default"
+		name := "tf-acc-resource"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`resource "k8s_resource" "test" {
+  manifest = {
+    apiVersion = "v1"
+    kind       = "ConfigMap"
+    metadata = {
+      namespace = "%s"
+      name      = "%s"
+    }
+    data = {
+      foo = "bar"
+    }
+  }
+}`, namespace, name),
+					ConfigStateChecks: []statecheck.StateCheck{
+						statecheck.ExpectKnownValue("k8s_resource.test", tfjsonpath.New("result").AtMapKey("metadata").AtMapKey("namespace"), knownvalue.StringExact(namespace)),
+						statecheck.ExpectKnownValue("k8s_resource.test", tfjsonpath.New("result").AtMapKey("metadata").AtMapKey("name"), knownvalue.StringExact(name)),
+						statecheck.ExpectKnownValue("k8s_resource.test", tfjsonpath.New("result").AtMapKey("data").AtMapKey("foo"), knownvalue.StringExact("bar")),
+					},
+				},
+				{
+					Config: fmt.Sprintf(`resource "k8s_resource" "test" {
+  manifest = {
+    apiVersion = "v1"
+    kind       = "ConfigMap"
+    metadata = {
+      namespace = "%s"
+      name      = "%s"
+    }
+    data = {
+      foo = "baz"
+    }
+  }
+}`, namespace, name),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("k8s_resource.test", plancheck.ResourceActionUpdate),
+							plancheck.ExpectKnownValue("k8s_resource.test", tfjsonpath.New("result").AtMapKey("data").AtMapKey("foo"), knownvalue.StringExact("baz")),
+						},
+					},
+					ConfigStateChecks: []statecheck.StateCheck{
+						statecheck.ExpectKnownValue("k8s_resource.test", tfjsonpath.New("result").AtMapKey("data").AtMapKey("foo"), knownvalue.StringExact("baz")),
+					},
+				},
+				{
+					ResourceName:    "k8s_resource.test",
+					ImportState:     true,
+					ImportStateKind: resource.ImportBlockWithID,
+					ImportStateId:   fmt.Sprintf("v1//ConfigMap//%s//%s", namespace, name),
+				},
+			},
+		})
+	})
+
+	t.Run("cluster_scoped_resource", func(t *testing.T) {
+		name := "tf-acc-resource"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`resource "k8s_resource" "test" {
+  manifest = {
+    apiVersion = "v1"
+    kind       = "Namespace"
+    metadata = {
+      name = "%s"
+    }
+  }
+}`, name),
+					ConfigStateChecks: []statecheck.StateCheck{
+						statecheck.ExpectKnownValue("k8s_resource.test", tfjsonpath.New("result").AtMapKey("metadata").AtMapKey("name"), knownvalue.StringExact(name)),
+						statecheck.ExpectIdentity("k8s_resource.test", map[string]knownvalue.Check{
+							"api_version": knownvalue.StringExact("v1"),
+							"kind":        knownvalue.StringExact("Namespace"),
+							"namespace":   knownvalue.Null(),
+							"name":        knownvalue.StringExact(name),
+						}),
+					},
+				},
+				{
+					ResourceName:    "k8s_resource.test",
+					ImportState:     true,
+					ImportStateKind: resource.ImportBlockWithResourceIdentity,
+				},
+			},
+		})
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		name := "tf-acc-resource-wait"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`resource "k8s_resource" "test" {
+  manifest = {
+    apiVersion = "v1"
+    kind       = "Namespace"
+    metadata = {
+      name = "%s"
+    }
+  }
+
+  wait = {
+    rollout = true
+    fields = {
+      "status.phase" = "Active"
+    }
+  }
+}`, name),
+					ConfigStateChecks: []statecheck.StateCheck{
+						statecheck.ExpectKnownValue("k8s_resource.test", tfjsonpath.New("result").AtMapKey("metadata").AtMapKey("name"), knownvalue.StringExact(name)),
+					},
+				},
+				{
+					Config: fmt.Sprintf(`resource "k8s_resource" "test" {
+  manifest = {
+    apiVersion = "v1"
+    kind       = "Namespace"
+    metadata = {
+      name = "%s"
+      labels = {
+        foo = "bar"
+      }
+    }
+  }
+
+  wait = {
+    conditions = [
+      {
+        type   = "Unknown"
+        status = "True"
+      }
+    ]
+  }
+
+  timeouts = {
+    update = "5s"
+  }
+}`, name),
+					ExpectError: regexp.MustCompile("Failed to wait for resource."),
+				},
+			},
+		})
+	})
+
+	t.Run("typed_result", func(t *testing.T) {
+		namespace := "default"
+		name := "tf-acc-resource-typed"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`resource "k8s_resource" "test" {
+  manifest = {
+    apiVersion = "v1"
+    kind       = "ConfigMap"
+    metadata = {
+      namespace = "%s"
+      name      = "%s"
+    }
+    data = {
+      foo = "bar"
+    }
+  }
+  typed_result = true
+}`, namespace, name),
+					ConfigStateChecks: []statecheck.StateCheck{
+						statecheck.ExpectKnownValue("k8s_resource.test", tfjsonpath.New("result").AtMapKey("metadata").AtMapKey("name"), knownvalue.StringExact(name)),
+						statecheck.ExpectKnownValue("k8s_resource.test", tfjsonpath.New("result").AtMapKey("data"), knownvalue.MapExact(map[string]knownvalue.Check{"foo": knownvalue.StringExact("bar")})),
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("custom_resource", func(t *testing.T) {
+		name := "tf-acc-resource-custom"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`resource "k8s_resource" "crd" {
+  manifest = {
+    apiVersion = "apiextensions.k8s.io/v1"
+    kind       = "CustomResourceDefinition"
+    metadata = {
+      name = "tfaccwidgets.example.com"
+    }
+    spec = {
+      group = "example.com"
+      names = {
+        kind     = "TfAccWidget"
+        plural   = "tfaccwidgets"
+        singular = "tfaccwidget"
+      }
+      scope = "Cluster"
+      versions = [
+        {
+          name    = "v1"
+          served  = true
+          storage = true
+          schema = {
+            openAPIV3Schema = {
+              type                                 = "object"
+              "x-kubernetes-preserve-unknown-fields" = true
+            }
+          }
+        }
+      ]
+    }
+  }
+}
+
+resource "k8s_resource" "test" {
+  manifest = {
+    apiVersion = "example.com/v1"
+    kind       = "TfAccWidget"
+    metadata = {
+      name = "%s"
+    }
+  }
+
+  depends_on = [k8s_resource.crd]
+}`, name),
+					ConfigStateChecks: []statecheck.StateCheck{
+						statecheck.ExpectKnownValue("k8s_resource.test", tfjsonpath.New("result").AtMapKey("metadata").AtMapKey("name"), knownvalue.StringExact(name)),
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("invalid_resource", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `resource "k8s_resource" "test" {
+  manifest = {
+    apiVersion = "v1"
+    kind       = "ConfigMap"
+    metadata = {
+      namespace = "default"
+      name      = "tf-acc-resource"
+    }
+    data = {
+      foo = ["bar"]
+    }
+  }
+}`,
+					PlanOnly:    true,
+					ExpectError: regexp.MustCompile("Failed to dry-run apply resource."),
+				},
+			},
+		})
+	})
+}
@@ -2,6 +2,7 @@ package tfutils
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
 
@@ -10,37 +11,74 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-// DecodeDynamic decodes an object into a Terraform dynamic value.
-func DecodeDynamic(ctx context.Context, obj any) (types.Dynamic, diag.Diagnostics) {
+// numberPrecision is the precision used for numbers which can't be represented exactly as an int64; this matches the
+// precision Terraform uses for numbers.
+const numberPrecision = 512
+
+// DecodeOption configures how values are decoded.
+type DecodeOption func(*decodeOptions)
+
+// decodeOptions are the options used to decode values.
+type decodeOptions struct {
+	keepEmpty bool
+}
+
+// WithEmptyCollections keeps empty mappings and sequences as empty objects and tuples instead of dropping them, as these
+// can be meaningful to the API server (e.g. `emptyDir: {}`).
+func WithEmptyCollections() DecodeOption {
+	return func(o *decodeOptions) {
+		o.keepEmpty = true
+	}
+}
+
+// newDecodeOptions returns the decode options with the given options applied.
+func newDecodeOptions(opts []DecodeOption) decodeOptions {
+	var o decodeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// DecodeDynamic decodes an object into a Terraform dynamic value; integers and JSON numbers are decoded without losing
+// precision. Empty mappings and sequences are dropped unless WithEmptyCollections is set.
+func DecodeDynamic(ctx context.Context, obj any, opts ...DecodeOption) (types.Dynamic, diag.Diagnostics) {
 	if obj == nil {
 		return types.DynamicNull(), nil
 	}
 
-	val, diags := decodeScalar(ctx, obj)
+	val, diags := decodeScalar(ctx, obj, newDecodeOptions(opts))
 	if diags.HasError() {
 		return types.Dynamic{}, diags
 	}
 
+	if val == nil {
+		return types.DynamicNull(), diags
+	}
+
 	return types.DynamicValue(val), diags
 }
 
-// decodeScalar decodes a scalar value into a Terraform attribute value.
-func decodeScalar(ctx context.Context, a any) (attr.Value, diag.Diagnostics) {
+// decodeScalar decodes a scalar value into a Terraform attribute value; this is nil for dropped empty collections.
+func decodeScalar(ctx context.Context, a any, opts decodeOptions) (attr.Value, diag.Diagnostics) {
 	switch v := a.(type) {
 	case nil:
 		return types.DynamicNull(), nil
 	case int64:
-		return types.NumberValue(big.NewFloat(float64(v))), nil
+		return types.NumberValue(new(big.Float).SetInt64(v)), nil
 	case float64:
 		return types.NumberValue(big.NewFloat(v)), nil
+	case json.Number:
+		return decodeNumber(v)
 	case bool:
 		return types.BoolValue(v), nil
 	case string:
 		return types.StringValue(v), nil
 	case []any:
-		return decodeSlice(ctx, v)
+		return decodeSlice(ctx, v, opts)
 	case map[string]any:
-		return decodeMap(ctx, v)
+		return decodeMap(ctx, v, opts)
 	default:
 		diagnostics := diag.Diagnostics{}
 		diagnostics.AddError("Unexpected type.", fmt.Sprintf("unexpected type: %T for value %#v", v, v))
@@ -48,16 +86,33 @@ func decodeScalar(ctx context.Context, a any) (attr.Value, diag.Diagnostics) {
 	}
 }
 
-// decodeSlice decodes a sequence value into a Terraform attribute value.
-func decodeSlice(ctx context.Context, s []any) (attr.Value, diag.Diagnostics) {
+// decodeNumber decodes a JSON number into a Terraform number value without losing precision.
+func decodeNumber(n json.Number) (attr.Value, diag.Diagnostics) {
+	if i, err := n.Int64(); err == nil {
+		return types.NumberValue(new(big.Float).SetInt64(i)), nil
+	}
+
+	f, _, err := big.ParseFloat(n.String(), 10, numberPrecision, big.ToNearestEven)
+	if err != nil {
+		diagnostics := diag.Diagnostics{}
+		diagnostics.AddError("Invalid number.", fmt.Sprintf("failed to parse number %q: %s", n.String(), err))
+		return nil, diagnostics
+	}
+
+	return types.NumberValue(f), nil
+}
+
+// decodeSlice decodes a sequence value into a Terraform attribute value; empty sequences are dropped unless the
+// options keep them.
+func decodeSlice(ctx context.Context, s []any, opts decodeOptions) (attr.Value, diag.Diagnostics) {
 	l := len(s)
 	vl := make([]attr.Value, 0, l)
 	tl := make([]attr.Type, 0, l)
 
 	for _, v := range s {
-		vv, err := decodeScalar(ctx, v)
-		if err != nil {
-			return nil, err
+		vv, diags := decodeScalar(ctx, v, opts)
+		if diags.HasError() {
+			return nil, diags
 		}
 
 		if vv != nil {
@@ -66,21 +121,22 @@ func decodeSlice(ctx context.Context, s []any) (attr.Value, diag.Diagnostics) {
 		}
 	}
 
-	if len(vl) > 0 {
-		return types.TupleValue(tl, vl)
+	if len(vl) == 0 && !opts.keepEmpty {
+		return nil, nil
 	}
 
-	return nil, nil
+	return types.TupleValue(tl, vl)
 }
 
-// decodeMap decodes a mapping value into a Terraform attribute value.
-func decodeMap(ctx context.Context, m map[string]any) (attr.Value, diag.Diagnostics) {
+// decodeMap decodes a mapping value into a Terraform attribute value; empty mappings are dropped unless the options
+// keep them.
+func decodeMap(ctx context.Context, m map[string]any, opts decodeOptions) (attr.Value, diag.Diagnostics) {
 	l := len(m)
 	vm := make(map[string]attr.Value, l)
 	tm := make(map[string]attr.Type, l)
 
 	for k, v := range m {
-		vv, diags := decodeScalar(ctx, v)
+		vv, diags := decodeScalar(ctx, v, opts)
 		if diags.HasError() {
 			return nil, diags
 		}
@@ -91,9 +147,9 @@ func decodeMap(ctx context.Context, m map[string]any) (attr.Value, diag.Diagnost
 		}
 	}
 
-	if len(vm) > 0 {
-		return types.ObjectValue(tm, vm)
+	if len(vm) == 0 && !opts.keepEmpty {
+		return nil, nil
 	}
 
-	return nil, nil
+	return types.ObjectValue(tm, vm)
 }
@@ -0,0 +1,427 @@
+package tfutils
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// SchemaResolver follows the references of an OpenAPI schema and returns the schema defining its type, or nil if the
+// references can't be resolved.
+type SchemaResolver func(sc *spec.Schema) *spec.Schema
+
+// DecodeDynamicWithSchema decodes an object into a Terraform dynamic value using its OpenAPI schema, so the value
+// types are consistent; arrays are decoded as lists and objects with additional properties as maps, with the types of
+// their elements unified by adding null attributes for the fields missing from some of the elements. Fields marked
+// with `x-kubernetes-int-or-string` or `x-kubernetes-preserve-unknown-fields`, fields without a schema, and arrays or
+// maps whose elements can't be unified are decoded in the same way as DecodeDynamic. The resolver is used to follow
+// schema references; if the schema is nil this is the same as DecodeDynamic.
+func DecodeDynamicWithSchema(ctx context.Context, obj any, sc *spec.Schema, resolve SchemaResolver, opts ...DecodeOption) (types.Dynamic, diag.Diagnostics) {
+	if obj == nil {
+		return types.DynamicNull(), nil
+	}
+
+	if sc == nil {
+		return DecodeDynamic(ctx, obj, opts...)
+	}
+
+	if resolve == nil {
+		resolve = func(sc *spec.Schema) *spec.Schema { return sc }
+	}
+
+	val, diags := decodeTyped(ctx, obj, sc, resolve, newDecodeOptions(opts))
+	if diags.HasError() {
+		return types.Dynamic{}, diags
+	}
+
+	if val == nil {
+		return types.DynamicNull(), diags
+	}
+
+	return types.DynamicValue(val), diags
+}
+
+// decodeTyped decodes a value into a Terraform attribute value using the given schema.
+func decodeTyped(ctx context.Context, a any, sc *spec.Schema, resolve SchemaResolver, opts decodeOptions) (attr.Value, diag.Diagnostics) {
+	if sc != nil {
+		sc = resolve(sc)
+	}
+
+	if sc == nil || isUntyped(sc) {
+		return decodeScalar(ctx, a, opts)
+	}
+
+	switch v := a.(type) {
+	case []any:
+		if sc.Items == nil || sc.Items.Schema == nil {
+			return decodeSlice(ctx, v, opts)
+		}
+
+		return decodeTypedSlice(ctx, v, sc.Items.Schema, resolve, opts)
+	case map[string]any:
+		if len(sc.Properties) > 0 {
+			return decodeTypedObject(ctx, v, sc, resolve, opts)
+		}
+
+		if sc.AdditionalProperties != nil && sc.AdditionalProperties.Schema != nil {
+			return decodeTypedMap(ctx, v, sc.AdditionalProperties.Schema, resolve, opts)
+		}
+
+		return decodeMap(ctx, v, opts)
+	default:
+		return decodeScalar(ctx, a, opts)
+	}
+}
+
+// decodeTypedSlice decodes a sequence value into a Terraform list value, or a tuple value if the element types can't
+// be unified.
+func decodeTypedSlice(ctx context.Context, sl []any, items *spec.Schema, resolve SchemaResolver, opts decodeOptions) (attr.Value, diag.Diagnostics) {
+	vl := make([]attr.Value, 0, len(sl))
+	for _, v := range sl {
+		vv, diags := decodeTyped(ctx, v, items, resolve, opts)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		if vv != nil {
+			vl = append(vl, vv)
+		}
+	}
+
+	if len(vl) == 0 && !opts.keepEmpty {
+		return nil, nil
+	}
+
+	t, ok := unifyValueTypes(ctx, vl)
+	if !ok {
+		return tupleValue(ctx, vl)
+	}
+
+	cl, diags := convertValues(ctx, vl, t)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	return types.ListValue(t, cl)
+}
+
+// decodeTypedMap decodes a mapping value with additional properties into a Terraform map value, or an object value
+// if the element types can't be unified.
+func decodeTypedMap(ctx context.Context, m map[string]any, elem *spec.Schema, resolve SchemaResolver, opts decodeOptions) (attr.Value, diag.Diagnostics) {
+	vm := make(map[string]attr.Value, len(m))
+	for k, v := range m {
+		vv, diags := decodeTyped(ctx, v, elem, resolve, opts)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		if vv != nil {
+			vm[k] = vv
+		}
+	}
+
+	if len(vm) == 0 && !opts.keepEmpty {
+		return nil, nil
+	}
+
+	t, ok := unifyValueTypes(ctx, slices.Collect(maps.Values(vm)))
+	if !ok {
+		return objectValue(ctx, vm)
+	}
+
+	cm := make(map[string]attr.Value, len(vm))
+	for k, v := range vm {
+		cv, diags := convertValue(ctx, v, t)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		cm[k] = cv
+	}
+
+	return types.MapValue(t, cm)
+}
+
+// decodeTypedObject decodes a mapping value with properties into a Terraform object value; fields without a property
+// schema are decoded without a schema.
+func decodeTypedObject(ctx context.Context, m map[string]any, sc *spec.Schema, resolve SchemaResolver, opts decodeOptions) (attr.Value, diag.Diagnostics) {
+	vm := make(map[string]attr.Value, len(m))
+	for k, v := range m {
+		var ps *spec.Schema
+		if p, ok := sc.Properties[k]; ok {
+			ps = &p
+		}
+
+		vv, diags := decodeTyped(ctx, v, ps, resolve, opts)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		if vv != nil {
+			vm[k] = vv
+		}
+	}
+
+	if len(vm) == 0 && !opts.keepEmpty {
+		return nil, nil
+	}
+
+	return objectValue(ctx, vm)
+}
+
+// isUntyped returns true if values of the schema can't be given a consistent type.
+func isUntyped(sc *spec.Schema) bool {
+	if v, _ := sc.Extensions.GetBool("x-kubernetes-int-or-string"); v {
+		return true
+	}
+
+	if v, _ := sc.Extensions.GetBool("x-kubernetes-preserve-unknown-fields"); v {
+		return true
+	}
+
+	return false
+}
+
+// unifyValueTypes returns a type all the values can be converted to; this is false if there are no non-null values
+// or their types can't be unified.
+func unifyValueTypes(ctx context.Context, vl []attr.Value) (attr.Type, bool) {
+	var t attr.Type
+
+	for _, v := range vl {
+		if t == nil {
+			t = v.Type(ctx)
+			continue
+		}
+
+		var ok bool
+		if t, ok = unifyTypes(t, v.Type(ctx)); !ok {
+			return nil, false
+		}
+	}
+
+	if t == nil || t.Equal(types.DynamicType) {
+		return nil, false
+	}
+
+	return t, true
+}
+
+// unifyTypes returns a type values of both types can be converted to; null values are decoded with the dynamic type
+// so this unifies with any type, objects unify to an object with all of their attributes, and empty tuples and
+// objects unify with lists and maps respectively.
+func unifyTypes(a, b attr.Type) (attr.Type, bool) {
+	switch {
+	case a.Equal(b):
+		return a, true
+	case a.Equal(types.DynamicType):
+		return b, true
+	case b.Equal(types.DynamicType):
+		return a, true
+	case isEmptyTuple(a) && isList(b):
+		return b, true
+	case isEmptyTuple(b) && isList(a):
+		return a, true
+	case isEmptyObject(a) && isMap(b):
+		return b, true
+	case isEmptyObject(b) && isMap(a):
+		return a, true
+	}
+
+	switch at := a.(type) {
+	case types.ObjectType:
+		bt, ok := b.(types.ObjectType)
+		if !ok {
+			return nil, false
+		}
+
+		m := maps.Clone(at.AttrTypes)
+		for k, t := range bt.AttrTypes {
+			if et, ok := m[k]; ok {
+				if t, ok = unifyTypes(et, t); !ok {
+					return nil, false
+				}
+			}
+
+			m[k] = t
+		}
+
+		return types.ObjectType{AttrTypes: m}, true
+	case types.ListType:
+		bt, ok := b.(types.ListType)
+		if !ok {
+			return nil, false
+		}
+
+		t, ok := unifyTypes(at.ElemType, bt.ElemType)
+		if !ok {
+			return nil, false
+		}
+
+		return types.ListType{ElemType: t}, true
+	case types.MapType:
+		bt, ok := b.(types.MapType)
+		if !ok {
+			return nil, false
+		}
+
+		t, ok := unifyTypes(at.ElemType, bt.ElemType)
+		if !ok {
+			return nil, false
+		}
+
+		return types.MapType{ElemType: t}, true
+	default:
+		return nil, false
+	}
+}
+
+// convertValues converts the values to the given type.
+func convertValues(ctx context.Context, vl []attr.Value, t attr.Type) ([]attr.Value, diag.Diagnostics) {
+	cl := make([]attr.Value, 0, len(vl))
+	for _, v := range vl {
+		cv, diags := convertValue(ctx, v, t)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		cl = append(cl, cv)
+	}
+
+	return cl, nil
+}
+
+// convertValue converts a value to the given type, which must have been unified with the value type.
+func convertValue(ctx context.Context, v attr.Value, t attr.Type) (attr.Value, diag.Diagnostics) {
+	if v.Type(ctx).Equal(t) {
+		return v, nil
+	}
+
+	if v.IsNull() {
+		return nullValue(ctx, t)
+	}
+
+	switch tt := t.(type) {
+	case types.ObjectType:
+		if ov, ok := v.(types.Object); ok {
+			attrs := ov.Attributes()
+			cm := make(map[string]attr.Value, len(tt.AttrTypes))
+			for k, at := range tt.AttrTypes {
+				var diags diag.Diagnostics
+				if a, ok := attrs[k]; ok {
+					cm[k], diags = convertValue(ctx, a, at)
+				} else {
+					cm[k], diags = nullValue(ctx, at)
+				}
+
+				if diags.HasError() {
+					return nil, diags
+				}
+			}
+
+			return types.ObjectValue(tt.AttrTypes, cm)
+		}
+	case types.ListType:
+		var vl []attr.Value
+		switch lv := v.(type) {
+		case types.List:
+			vl = lv.Elements()
+		case types.Tuple:
+			vl = lv.Elements()
+		}
+
+		cl, diags := convertValues(ctx, vl, tt.ElemType)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		return types.ListValue(tt.ElemType, cl)
+	case types.MapType:
+		var vm map[string]attr.Value
+		switch mv := v.(type) {
+		case types.Map:
+			vm = mv.Elements()
+		case types.Object:
+			vm = mv.Attributes()
+		}
+
+		cm := make(map[string]attr.Value, len(vm))
+		for k, e := range vm {
+			cv, diags := convertValue(ctx, e, tt.ElemType)
+			if diags.HasError() {
+				return nil, diags
+			}
+
+			cm[k] = cv
+		}
+
+		return types.MapValue(tt.ElemType, cm)
+	}
+
+	diagnostics := diag.Diagnostics{}
+	diagnostics.AddError("Unexpected type.", fmt.Sprintf("can't convert %s to %s", v.Type(ctx), t))
+	return nil, diagnostics
+}
+
+// nullValue returns a null value of the given type.
+func nullValue(ctx context.Context, t attr.Type) (attr.Value, diag.Diagnostics) {
+	v, err := t.ValueFromTerraform(ctx, tftypes.NewValue(t.TerraformType(ctx), nil))
+	if err != nil {
+		diagnostics := diag.Diagnostics{}
+		diagnostics.AddError("Unexpected type.", fmt.Sprintf("failed to create null value of %s: %s", t, err))
+		return nil, diagnostics
+	}
+
+	return v, nil
+}
+
+// tupleValue returns a tuple value of the values.
+func tupleValue(ctx context.Context, vl []attr.Value) (attr.Value, diag.Diagnostics) {
+	tl := make([]attr.Type, 0, len(vl))
+	for _, v := range vl {
+		tl = append(tl, v.Type(ctx))
+	}
+
+	return types.TupleValue(tl, vl)
+}
+
+// objectValue returns an object value of the values.
+func objectValue(ctx context.Context, vm map[string]attr.Value) (attr.Value, diag.Diagnostics) {
+	tm := make(map[string]attr.Type, len(vm))
+	for k, v := range vm {
+		tm[k] = v.Type(ctx)
+	}
+
+	return types.ObjectValue(tm, vm)
+}
+
+// isEmptyTuple returns true if the type is a tuple without elements.
+func isEmptyTuple(t attr.Type) bool {
+	tt, ok := t.(types.TupleType)
+	return ok && len(tt.ElemTypes) == 0
+}
+
+// isEmptyObject returns true if the type is an object without attributes.
+func isEmptyObject(t attr.Type) bool {
+	ot, ok := t.(types.ObjectType)
+	return ok && len(ot.AttrTypes) == 0
+}
+
+// isList returns true if the type is a list.
+func isList(t attr.Type) bool {
+	_, ok := t.(types.ListType)
+	return ok
+}
+
+// isMap returns true if the type is a map.
+func isMap(t attr.Type) bool {
+	_, ok := t.(types.MapType)
+	return ok
+}
@@ -0,0 +1,178 @@
+package tfutils
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestDecodeDynamicWithSchema(t *testing.T) {
+	t.Parallel()
+
+	intOrString := spec.Schema{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{"x-kubernetes-int-or-string": true}}}
+	preserveUnknownFields := spec.Schema{
+		SchemaProps:      spec.SchemaProps{Type: []string{"object"}},
+		VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{"x-kubernetes-preserve-unknown-fields": true}},
+	}
+
+	container := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"name":  *spec.StringProperty(),
+			"image": *spec.StringProperty(),
+			"args":  *spec.ArrayProperty(spec.StringProperty()),
+			"port":  intOrString,
+		},
+	}}
+
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"containers": *spec.ArrayProperty(spec.RefSchema("#/components/schemas/container")),
+			"labels":     *spec.MapProperty(spec.StringProperty()),
+			"extra":      preserveUnknownFields,
+			"items":      *spec.ArrayProperty(&intOrString),
+		},
+	}}
+
+	// The container schema is only reachable through its reference.
+	resolve := func(sc *spec.Schema) *spec.Schema {
+		if sc.Ref.String() == "#/components/schemas/container" {
+			return container
+		}
+
+		return sc
+	}
+
+	containerType := types.ObjectType{AttrTypes: map[string]attr.Type{
+		"name":  types.StringType,
+		"image": types.StringType,
+		"args":  types.ListType{ElemType: types.StringType},
+	}}
+
+	containers := types.ListValueMust(containerType, []attr.Value{
+		types.ObjectValueMust(containerType.AttrTypes, map[string]attr.Value{
+			"name":  types.StringValue("foo"),
+			"image": types.StringValue("foo:latest"),
+			"args":  types.ListNull(types.StringType),
+		}),
+		types.ObjectValueMust(containerType.AttrTypes, map[string]attr.Value{
+			"name":  types.StringValue("bar"),
+			"image": types.StringNull(),
+			"args":  types.ListValueMust(types.StringType, []attr.Value{types.StringValue("--foo")}),
+		}),
+	})
+
+	for _, d := range []struct {
+		testName string
+		in       any
+		schema   *spec.Schema
+		want     types.Dynamic
+		errMsg   string
+	}{
+		{
+			testName: "null",
+			in:       nil,
+			schema:   s,
+			want:     types.DynamicNull(),
+		},
+		{
+			testName: "no_schema",
+			in:       map[string]any{"containers": []any{"foo"}},
+			want:     types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{"containers": types.TupleType{ElemTypes: []attr.Type{types.StringType}}}, map[string]attr.Value{"containers": types.TupleValueMust([]attr.Type{types.StringType}, []attr.Value{types.StringValue("foo")})})),
+		},
+		{
+			testName: "list_of_objects",
+			in: map[string]any{"containers": []any{
+				map[string]any{"name": "foo", "image": "foo:latest"},
+				map[string]any{"name": "bar", "args": []any{"--foo"}},
+			}},
+			schema: s,
+			want:   types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{"containers": containers.Type(t.Context())}, map[string]attr.Value{"containers": containers})),
+		},
+		{
+			testName: "list_empty",
+			in:       map[string]any{"containers": []any{}},
+			schema:   s,
+			want:     types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{"containers": types.TupleType{ElemTypes: []attr.Type{}}}, map[string]attr.Value{"containers": types.TupleValueMust([]attr.Type{}, []attr.Value{})})),
+		},
+		{
+			testName: "map",
+			in:       map[string]any{"labels": map[string]any{"foo": "bar"}},
+			schema:   s,
+			want:     types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{"labels": types.MapType{ElemType: types.StringType}}, map[string]attr.Value{"labels": types.MapValueMust(types.StringType, map[string]attr.Value{"foo": types.StringValue("bar")})})),
+		},
+		{
+			testName: "int_or_string",
+			in:       map[string]any{"items": []any{int64(80), "http"}},
+			schema:   s,
+			want:     types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{"items": types.TupleType{ElemTypes: []attr.Type{types.NumberType, types.StringType}}}, map[string]attr.Value{"items": types.TupleValueMust([]attr.Type{types.NumberType, types.StringType}, []attr.Value{types.NumberValue(big.NewFloat(80)), types.StringValue("http")})})),
+		},
+		{
+			testName: "preserve_unknown_fields",
+			in:       map[string]any{"extra": map[string]any{"foo": []any{"bar"}}},
+			schema:   s,
+			want:     types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{"extra": types.ObjectType{AttrTypes: map[string]attr.Type{"foo": types.TupleType{ElemTypes: []attr.Type{types.StringType}}}}}, map[string]attr.Value{"extra": types.ObjectValueMust(map[string]attr.Type{"foo": types.TupleType{ElemTypes: []attr.Type{types.StringType}}}, map[string]attr.Value{"foo": types.TupleValueMust([]attr.Type{types.StringType}, []attr.Value{types.StringValue("bar")})})})),
+		},
+		{
+			testName: "unknown_field",
+			in:       map[string]any{"foo": []any{"bar"}},
+			schema:   s,
+			want:     types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{"foo": types.TupleType{ElemTypes: []attr.Type{types.StringType}}}, map[string]attr.Value{"foo": types.TupleValueMust([]attr.Type{types.StringType}, []attr.Value{types.StringValue("bar")})})),
+		},
+		{
+			testName: "unexpected_type",
+			in:       map[string]any{"labels": map[string]any{"foo": 1}},
+			schema:   s,
+			want:     types.Dynamic{},
+			errMsg:   "Unexpected type.",
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+
+			got, diags := DecodeDynamicWithSchema(ctx, d.in, d.schema, resolve, WithEmptyCollections())
+
+			if !got.Equal(d.want) {
+				t.Errorf("DecodeDynamicWithSchema returned:\n%v\nwant:\n%v", got, d.want)
+			}
+
+			var errMsg string
+			if diags.HasError() {
+				for i, diag := range diags.Errors() {
+					if i == 0 {
+						errMsg = diag.Summary()
+						continue
+					}
+					errMsg = fmt.Sprintf("%s: %s", errMsg, diag.Summary())
+				}
+			}
+
+			if errMsg != d.errMsg {
+				t.Errorf("DecodeDynamicWithSchema returned error message %q, want %q", errMsg, d.errMsg)
+			}
+
+			if diags.HasError() || d.in == nil {
+				return
+			}
+
+			rt, diags := EncodeDynamic(ctx, path.Empty(), got)
+			if diags.HasError() {
+				t.Fatalf("EncodeDynamic returned unexpected error: %v", diags)
+			}
+
+			if diff := cmp.Diff(d.in, rt); diff != "" {
+				t.Errorf("EncodeDynamic(DecodeDynamicWithSchema()) mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
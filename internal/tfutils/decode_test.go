@@ -1,6 +1,7 @@
 package tfutils
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"testing"
@@ -14,10 +15,14 @@ func TestDecodeDynamic(t *testing.T) {
 
 	simpleObject, _ := types.ObjectValue(map[string]attr.Type{"foo": types.StringType}, map[string]attr.Value{"foo": types.StringValue("bar")})
 	stringTuple, _ := types.TupleValue([]attr.Type{types.StringType, types.StringType}, []attr.Value{types.StringValue("foo"), types.StringValue("bar")})
+	emptyObject, _ := types.ObjectValue(map[string]attr.Type{}, map[string]attr.Value{})
+	emptyTuple, _ := types.TupleValue([]attr.Type{}, []attr.Value{})
+	nestedEmptyObject, _ := types.ObjectValue(map[string]attr.Type{"emptyDir": emptyObject.Type(t.Context()), "args": emptyTuple.Type(t.Context())}, map[string]attr.Value{"emptyDir": emptyObject, "args": emptyTuple})
 
 	for _, d := range []struct {
 		testName string
 		in       any
+		opts     []DecodeOption
 		want     types.Dynamic
 		errMsg   string
 	}{
@@ -37,6 +42,32 @@ func TestDecodeDynamic(t *testing.T) {
 			in:       int64(1),
 			want:     types.DynamicValue(types.NumberValue(big.NewFloat(float64(1)))),
 		},
+		{
+			testName: "int64_large",
+			in:       int64(9007199254740993),
+			want:     types.DynamicValue(types.NumberValue(new(big.Float).SetInt64(9007199254740993))),
+		},
+		{
+			testName: "json_number_integer",
+			in:       json.Number("9007199254740993"),
+			want:     types.DynamicValue(types.NumberValue(new(big.Float).SetInt64(9007199254740993))),
+		},
+		{
+			testName: "json_number_large_integer",
+			in:       json.Number("18446744073709551616"),
+			want:     types.DynamicValue(types.NumberValue(new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), 64)))),
+		},
+		{
+			testName: "json_number_float",
+			in:       json.Number("1.5"),
+			want:     types.DynamicValue(types.NumberValue(big.NewFloat(1.5))),
+		},
+		{
+			testName: "json_number_invalid",
+			in:       json.Number("foo"),
+			want:     types.Dynamic{},
+			errMsg:   "Invalid number.",
+		},
 		{
 			testName: "float64",
 			in:       float64(1.1),
@@ -57,6 +88,12 @@ func TestDecodeDynamic(t *testing.T) {
 			in:       map[string]any{},
 			want:     types.DynamicNull(),
 		},
+		{
+			testName: "object_empty_kept",
+			in:       map[string]any{},
+			opts:     []DecodeOption{WithEmptyCollections()},
+			want:     types.DynamicValue(emptyObject),
+		},
 		{
 			testName: "object_simple",
 			in:       map[string]any{"foo": "bar"},
@@ -67,18 +104,40 @@ func TestDecodeDynamic(t *testing.T) {
 			in:       []any{},
 			want:     types.DynamicNull(),
 		},
+		{
+			testName: "array_empty_kept",
+			in:       []any{},
+			opts:     []DecodeOption{WithEmptyCollections()},
+			want:     types.DynamicValue(emptyTuple),
+		},
 		{
 			testName: "array_strings",
 			in:       []any{"foo", "bar"},
 			want:     types.DynamicValue(stringTuple),
 		},
+		{
+			testName: "object_nested_empty",
+			in:       map[string]any{"foo": "bar", "emptyDir": map[string]any{}, "args": []any{}},
+			want:     types.DynamicValue(simpleObject),
+		},
+		{
+			testName: "object_nested_empty_kept",
+			in:       map[string]any{"emptyDir": map[string]any{}, "args": []any{}},
+			opts:     []DecodeOption{WithEmptyCollections()},
+			want:     types.DynamicValue(nestedEmptyObject),
+		},
+		{
+			testName: "array_null_element",
+			in:       []any{"foo", nil},
+			want:     types.DynamicValue(types.TupleValueMust([]attr.Type{types.StringType, types.DynamicType}, []attr.Value{types.StringValue("foo"), types.DynamicNull()})),
+		},
 	} {
 		t.Run(d.testName, func(t *testing.T) {
 			t.Parallel()
 
 			ctx := t.Context()
 
-			got, diags := DecodeDynamic(ctx, d.in)
+			got, diags := DecodeDynamic(ctx, d.in, d.opts...)
 
 			if !got.Equal(d.want) {
 				t.Errorf("DecodeDynamic returned:\n%v\nwant:\n%v", got, d.want)
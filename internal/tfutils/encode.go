@@ -0,0 +1,135 @@
+package tfutils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// EncodeDynamic encodes a Terraform dynamic value into an object; objects and maps are encoded as `map[string]any`,
+// tuples, lists and sets as `[]any`, integer numbers as `int64` (or `json.Number` if they overflow an `int64`) and
+// all other numbers as `float64`. Diagnostics are reported against the path of the value that can't be encoded,
+// relative to the given path.
+func EncodeDynamic(ctx context.Context, p path.Path, val types.Dynamic) (any, diag.Diagnostics) {
+	if val.IsNull() || val.IsUnderlyingValueNull() {
+		return nil, nil
+	}
+
+	if val.IsUnknown() || val.IsUnderlyingValueUnknown() {
+		diagnostics := diag.Diagnostics{}
+		diagnostics.AddAttributeError(p, "Unexpected unknown value.", "unknown values can't be encoded")
+		return nil, diagnostics
+	}
+
+	return encodeValue(ctx, p, val.UnderlyingValue())
+}
+
+// encodeValue encodes a Terraform attribute value into an object.
+func encodeValue(ctx context.Context, p path.Path, a attr.Value) (any, diag.Diagnostics) {
+	if a.IsUnknown() {
+		diagnostics := diag.Diagnostics{}
+		diagnostics.AddAttributeError(p, "Unexpected unknown value.", "unknown values can't be encoded")
+		return nil, diagnostics
+	}
+
+	if a.IsNull() {
+		return nil, nil
+	}
+
+	switch v := a.(type) {
+	case types.Dynamic:
+		return EncodeDynamic(ctx, p, v)
+	case types.Bool:
+		return v.ValueBool(), nil
+	case types.String:
+		return v.ValueString(), nil
+	case types.Number:
+		return encodeNumber(v.ValueBigFloat()), nil
+	case types.Tuple:
+		return encodeSlice(ctx, p, v.Elements())
+	case types.List:
+		return encodeSlice(ctx, p, v.Elements())
+	case types.Set:
+		return encodeSet(ctx, p, v.Elements())
+	case types.Object:
+		return encodeMap(ctx, v.Attributes(), p.AtName)
+	case types.Map:
+		return encodeMap(ctx, v.Elements(), p.AtMapKey)
+	default:
+		diagnostics := diag.Diagnostics{}
+		diagnostics.AddAttributeError(p, "Unexpected type.", fmt.Sprintf("unexpected type: %T for value %s", v, v))
+		return nil, diagnostics
+	}
+}
+
+// encodeNumber encodes a number as an int64 if it is an integer in the int64 range, as a JSON number if it is a larger
+// integer so no precision is lost, otherwise as a float64.
+func encodeNumber(f *big.Float) any {
+	if f.IsInt() {
+		if i, acc := f.Int64(); acc == big.Exact {
+			return i
+		}
+
+		return json.Number(f.Text('f', 0))
+	}
+
+	v, _ := f.Float64()
+	return v
+}
+
+// encodeSlice encodes a sequence of Terraform attribute values into a slice.
+func encodeSlice(ctx context.Context, p path.Path, l []attr.Value) (any, diag.Diagnostics) {
+	s := make([]any, 0, len(l))
+
+	for i, v := range l {
+		vv, diags := encodeValue(ctx, p.AtListIndex(i), v)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		s = append(s, vv)
+	}
+
+	return s, nil
+}
+
+// encodeSet encodes a set of Terraform attribute values into a slice.
+func encodeSet(ctx context.Context, p path.Path, l []attr.Value) (any, diag.Diagnostics) {
+	s := make([]any, 0, len(l))
+
+	for _, v := range l {
+		vv, diags := encodeValue(ctx, p.AtSetValue(v), v)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		s = append(s, vv)
+	}
+
+	return s, nil
+}
+
+// encodeMap encodes a mapping of Terraform attribute values into a map, using at to get the path of each value; null
+// values are omitted.
+func encodeMap(ctx context.Context, m map[string]attr.Value, at func(string) path.Path) (any, diag.Diagnostics) {
+	o := make(map[string]any, len(m))
+
+	for k, v := range m {
+		vv, diags := encodeValue(ctx, at(k), v)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		if vv != nil {
+			o[k] = vv
+		}
+	}
+
+	return o, nil
+}
@@ -0,0 +1,296 @@
+package tfutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestEncodeDynamic(t *testing.T) {
+	t.Parallel()
+
+	simpleObject, _ := types.ObjectValue(map[string]attr.Type{"foo": types.StringType, "bar": types.StringType}, map[string]attr.Value{"foo": types.StringValue("bar"), "bar": types.StringNull()})
+	stringTuple, _ := types.TupleValue([]attr.Type{types.StringType, types.StringType}, []attr.Value{types.StringValue("foo"), types.StringValue("bar")})
+	nullTuple, _ := types.TupleValue([]attr.Type{types.StringType, types.StringType}, []attr.Value{types.StringValue("foo"), types.StringNull()})
+	unknownObject, _ := types.ObjectValue(map[string]attr.Type{"foo": types.StringType}, map[string]attr.Value{"foo": types.StringUnknown()})
+	stringList, _ := types.ListValue(types.StringType, []attr.Value{types.StringValue("foo"), types.StringValue("bar")})
+	numberSet, _ := types.SetValue(types.NumberType, []attr.Value{types.NumberValue(big.NewFloat(1))})
+	stringMap, _ := types.MapValue(types.StringType, map[string]attr.Value{"foo": types.StringValue("bar"), "baz": types.StringNull()})
+	unknownList, _ := types.ListValue(types.StringType, []attr.Value{types.StringValue("foo"), types.StringUnknown()})
+	unknownMap, _ := types.MapValue(types.StringType, map[string]attr.Value{"foo": types.StringUnknown()})
+	unknownListObject, _ := types.ObjectValue(map[string]attr.Type{"foo": unknownList.Type(t.Context())}, map[string]attr.Value{"foo": unknownList})
+	unexpectedObject, _ := types.ObjectValue(map[string]attr.Type{"foo": types.Int64Type}, map[string]attr.Value{"foo": types.Int64Value(1)})
+	largeInteger, _ := new(big.Float).SetString("1e20")
+
+	for _, d := range []struct {
+		testName string
+		in       types.Dynamic
+		want     any
+		errMsg   string
+		errPath  path.Path
+	}{
+		{
+			testName: "null",
+			in:       types.DynamicNull(),
+			want:     nil,
+		},
+		{
+			testName: "null_underlying_value",
+			in:       types.DynamicValue(types.StringNull()),
+			want:     nil,
+		},
+		{
+			testName: "unknown",
+			in:       types.DynamicUnknown(),
+			want:     nil,
+			errMsg:   "Unexpected unknown value.",
+			errPath:  path.Root("manifest"),
+		},
+		{
+			testName: "unknown_underlying_value",
+			in:       types.DynamicValue(types.StringUnknown()),
+			want:     nil,
+			errMsg:   "Unexpected unknown value.",
+			errPath:  path.Root("manifest"),
+		},
+		{
+			testName: "integer",
+			in:       types.DynamicValue(types.NumberValue(big.NewFloat(1))),
+			want:     int64(1),
+		},
+		{
+			testName: "negative_integer",
+			in:       types.DynamicValue(types.NumberValue(big.NewFloat(-10))),
+			want:     int64(-10),
+		},
+		{
+			testName: "float",
+			in:       types.DynamicValue(types.NumberValue(big.NewFloat(1.1))),
+			want:     float64(1.1),
+		},
+		{
+			testName: "integer_out_of_range",
+			in:       types.DynamicValue(types.NumberValue(largeInteger)),
+			want:     json.Number("100000000000000000000"),
+		},
+		{
+			testName: "bool",
+			in:       types.DynamicValue(types.BoolValue(true)),
+			want:     true,
+		},
+		{
+			testName: "string",
+			in:       types.DynamicValue(types.StringValue("foo")),
+			want:     "foo",
+		},
+		{
+			testName: "object_simple",
+			in:       types.DynamicValue(simpleObject),
+			want:     map[string]any{"foo": "bar"},
+		},
+		{
+			testName: "object_unknown_attribute",
+			in:       types.DynamicValue(unknownObject),
+			want:     nil,
+			errMsg:   "Unexpected unknown value.",
+			errPath:  path.Root("manifest").AtName("foo"),
+		},
+		{
+			testName: "tuple_strings",
+			in:       types.DynamicValue(stringTuple),
+			want:     []any{"foo", "bar"},
+		},
+		{
+			testName: "tuple_null_element",
+			in:       types.DynamicValue(nullTuple),
+			want:     []any{"foo", nil},
+		},
+		{
+			testName: "list_strings",
+			in:       types.DynamicValue(stringList),
+			want:     []any{"foo", "bar"},
+		},
+		{
+			testName: "list_unknown_element",
+			in:       types.DynamicValue(unknownListObject),
+			want:     nil,
+			errMsg:   "Unexpected unknown value.",
+			errPath:  path.Root("manifest").AtName("foo").AtListIndex(1),
+		},
+		{
+			testName: "set_numbers",
+			in:       types.DynamicValue(numberSet),
+			want:     []any{int64(1)},
+		},
+		{
+			testName: "map_strings",
+			in:       types.DynamicValue(stringMap),
+			want:     map[string]any{"foo": "bar"},
+		},
+		{
+			testName: "map_unknown_element",
+			in:       types.DynamicValue(unknownMap),
+			want:     nil,
+			errMsg:   "Unexpected unknown value.",
+			errPath:  path.Root("manifest").AtMapKey("foo"),
+		},
+		{
+			testName: "unexpected_type",
+			in:       types.DynamicValue(unexpectedObject),
+			want:     nil,
+			errMsg:   "Unexpected type.",
+			errPath:  path.Root("manifest").AtName("foo"),
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+
+			got, diags := EncodeDynamic(ctx, path.Root("manifest"), d.in)
+
+			if diff := cmp.Diff(d.want, got); diff != "" {
+				t.Errorf("EncodeDynamic() mismatch (-want +got):\n%s", diff)
+			}
+
+			var errMsg string
+			var errPath path.Path
+			if diags.HasError() {
+				for i, diag := range diags.Errors() {
+					if i == 0 {
+						errMsg = diag.Summary()
+						errPath = diagPath(diag)
+						continue
+					}
+					errMsg = fmt.Sprintf("%s: %s", errMsg, diag.Summary())
+				}
+			}
+
+			if errMsg != d.errMsg {
+				t.Errorf("EncodeDynamic returned error message %q, want %q", errMsg, d.errMsg)
+			}
+
+			if !errPath.Equal(d.errPath) {
+				t.Errorf("EncodeDynamic returned error path %q, want %q", errPath, d.errPath)
+			}
+		})
+	}
+}
+
+func TestEncodeDynamicRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []struct {
+		testName string
+		in       any
+	}{
+		{
+			testName: "int64",
+			in:       int64(1),
+		},
+		{
+			testName: "int64_large",
+			in:       int64(9007199254740993),
+		},
+		{
+			testName: "int64_max",
+			in:       int64(math.MaxInt64),
+		},
+		{
+			testName: "int64_min",
+			in:       int64(math.MinInt64),
+		},
+		{
+			testName: "float64",
+			in:       float64(1.1),
+		},
+		{
+			testName: "json_number_large_integer",
+			in:       json.Number("18446744073709551616"),
+		},
+		{
+			testName: "bool",
+			in:       true,
+		},
+		{
+			testName: "string",
+			in:       "foo",
+		},
+		{
+			testName: "object_simple",
+			in:       map[string]any{"foo": "bar"},
+		},
+		{
+			testName: "array_strings",
+			in:       []any{"foo", "bar"},
+		},
+		{
+			testName: "object_empty",
+			in:       map[string]any{},
+		},
+		{
+			testName: "array_empty",
+			in:       []any{},
+		},
+		{
+			testName: "nested_empty",
+			in:       map[string]any{"volumes": []any{map[string]any{"name": "tmp", "emptyDir": map[string]any{}}}, "args": []any{}},
+		},
+		{
+			testName: "nested",
+			in: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "foo", "labels": map[string]any{"app.kubernetes.io/name": "foo"}},
+				"spec": map[string]any{
+					"replicas": int64(3),
+					"template": map[string]any{
+						"spec": map[string]any{
+							"containers": []any{
+								map[string]any{"name": "foo", "image": "foo:latest", "ports": []any{map[string]any{"containerPort": int64(8080)}}},
+								map[string]any{"name": "bar", "args": []any{"--ratio", float64(0.5), true}},
+							},
+						},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(d.testName, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+
+			val, diags := DecodeDynamic(ctx, d.in, WithEmptyCollections())
+			if diags.HasError() {
+				t.Fatalf("DecodeDynamic returned unexpected error: %v", diags)
+			}
+
+			got, diags := EncodeDynamic(ctx, path.Empty(), val)
+			if diags.HasError() {
+				t.Fatalf("EncodeDynamic returned unexpected error: %v", diags)
+			}
+
+			if diff := cmp.Diff(d.in, got); diff != "" {
+				t.Errorf("EncodeDynamic(DecodeDynamic()) mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// diagPath returns the path of the diagnostic if it has one.
+func diagPath(d diag.Diagnostic) path.Path {
+	if dp, ok := d.(diag.DiagnosticWithPath); ok {
+		return dp.Path()
+	}
+
+	return path.Empty()
+}